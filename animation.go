@@ -2,6 +2,8 @@ package main
 
 import (
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,52 +22,81 @@ func animTickCmd() tea.Cmd {
 // ── Snapshot diffing ────────────────────────────────────────
 
 type snapshot struct {
-	files map[string]string // path → status
+	files  map[string]fileEntry // path → status, diffstat and blob
+	origin string               // commit files outside the list match
 }
 
 func newSnapshot(files []fileEntry) snapshot {
-	m := make(map[string]string, len(files))
+	m := make(map[string]fileEntry, len(files))
 	for _, f := range files {
-		m[f.path] = f.status
+		m[f.path] = f
 	}
 	return snapshot{files: m}
 }
 
+// diff reports the files that joined, left or changed in the list since s.
+// A file changes when its status, diffstat or captured content does. Events
+// come sorted by path.
 func (s snapshot) diff(current []fileEntry) []changeEvent {
+	return s.changes(newSnapshot(current))
+}
+
+// changes is diff against a whole snapshot, which knows what files outside
+// the list looked like.
+func (s snapshot) changes(next snapshot) []changeEvent {
 	now := time.Now()
 	var events []changeEvent
 
-	currentMap := make(map[string]string, len(current))
-	for _, f := range current {
-		currentMap[f.path] = f.status
-	}
-
 	// Detect added or changed
-	for path, status := range currentMap {
-		oldStatus, existed := s.files[path]
+	for path, f := range next.files {
+		old, existed := s.files[path]
 		if !existed {
-			events = append(events, changeEvent{path: path, status: status, at: now})
-		} else if oldStatus != status {
-			events = append(events, changeEvent{path: path, status: status, at: now})
+			events = append(events, changeEvent{path: path, status: f.status, stat: f.stat,
+				oldBlob: originObject(s.origin, path), blob: f.blob, at: now})
+		} else if old.status != f.status || old.stat != f.stat || old.blob != f.blob {
+			events = append(events, changeEvent{path: path, oldStatus: old.status, status: f.status, stat: f.stat,
+				oldBlob: old.blob, blob: f.blob, at: now})
 		}
 	}
 
 	// Detect removed
-	for path := range s.files {
-		if _, exists := currentMap[path]; !exists {
-			events = append(events, changeEvent{path: path, status: "D", at: now})
+	for path, old := range s.files {
+		if _, exists := next.files[path]; !exists {
+			events = append(events, changeEvent{path: path, oldStatus: old.status,
+				oldBlob: old.blob, blob: originObject(next.origin, path), at: now})
 		}
 	}
 
+	// Maps have no order; report a refresh's events by path
+	slices.SortFunc(events, func(a, b changeEvent) int { return strings.Compare(a.path, b.path) })
 	return events
 }
 
 // ── Change events ───────────────────────────────────────────
 
+// changeEvent records a file entering, changing in or leaving the list. An
+// empty status means the file wasn't in the list: oldStatus is empty when it
+// was added, status when it was removed (committed, reverted or deleted
+// while untracked).
 type changeEvent struct {
-	path   string
-	status string
-	at     time.Time
+	path      string
+	oldStatus string
+	status    string
+	stat      diffStat // against HEAD or the base, after the change
+	oldBlob   string   // object names of the content before and after, "" if unknown
+	blob      string
+	at        time.Time
+}
+
+// kind names the event: "added", "changed" or "removed".
+func (e changeEvent) kind() string {
+	switch {
+	case e.oldStatus == "" && e.status != "":
+		return "added"
+	case e.status == "":
+		return "removed"
+	}
+	return "changed"
 }
 
 // ── Events ring buffer ──────────────────────────────────────
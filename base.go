@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// refEntry is one choice in the base picker. The empty name stands for HEAD,
+// i.e. only uncommitted changes.
+type refEntry struct {
+	name string
+}
+
+func (r refEntry) FilterValue() string { return r.name }
+
+type refsLoadedMsg struct {
+	refs []string
+	err  error
+}
+
+// baseChosenMsg reports a validated base, or why it can't be used.
+type baseChosenMsg struct {
+	base string
+	err  error
+}
+
+func loadRefs() tea.Cmd {
+	return func() tea.Msg {
+		refs, err := getRefs()
+		return refsLoadedMsg{refs: refs, err: err}
+	}
+}
+
+// refItems lists the picker's choices: HEAD first, then refs.
+func refItems(refs []string) []list.Item {
+	items := []list.Item{refEntry{}}
+	for _, r := range refs {
+		items = append(items, refEntry{name: r})
+	}
+	return items
+}
+
+// chooseBaseCmd checks base resolves before switching to it.
+func chooseBaseCmd(base string) tea.Cmd {
+	return func() tea.Msg {
+		if base != "" {
+			if _, err := resolveBase(base); err != nil {
+				return baseChosenMsg{err: err}
+			}
+		}
+		return baseChosenMsg{base: base}
+	}
+}
+
+// refDelegate renders picker rows, marking the ref currently compared against.
+type refDelegate struct {
+	current string
+}
+
+func (d refDelegate) Height() int                             { return 1 }
+func (d refDelegate) Spacing() int                            { return 0 }
+func (d refDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d refDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	r, ok := item.(refEntry)
+	if !ok {
+		return
+	}
+	isSelected := index == m.Index()
+
+	prefix := "  "
+	if isSelected {
+		prefix = cursorStyle.Render("> ")
+	}
+	nameStyle := pathFileStyle
+	if isSelected {
+		nameStyle = nameStyle.Background(colorHighlight)
+	}
+	row := prefix
+	if r.name == "" {
+		row += nameStyle.Render("HEAD") + headerDimStyle.Render("  uncommitted changes only")
+	} else {
+		row += nameStyle.Render(r.name)
+	}
+	if current := strings.TrimSuffix(d.current, "..."); r.name == current {
+		mark := "  ● current"
+		if strings.HasSuffix(d.current, "...") {
+			mark += " (merge-base)"
+		}
+		row += cleanIndicatorStyle.Render(mark)
+	}
+
+	if isSelected {
+		if rowLen := lipgloss.Width(row); rowLen < m.Width() {
+			row += selectedRowStyle.Render(strings.Repeat(" ", m.Width()-rowLen))
+		}
+	}
+	fmt.Fprint(w, row)
+}
+
+// openBasePicker lists refs to compare the worktree against.
+func (m model) openBasePicker() (tea.Model, tea.Cmd) {
+	m.currentView = baseView
+	m.baseList.ResetFilter()
+	return m, loadRefs()
+}
+
+// updateBasePicker handles keys in the base picker: enter compares against
+// the ref itself, merge-base against where the branch forked from it.
+func (m model) updateBasePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Back):
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.baseList.FilterState() == list.FilterApplied {
+			m.baseList.ResetFilter()
+			return m, nil
+		}
+		m.currentView = fileListView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Open):
+		r, ok := m.baseList.SelectedItem().(refEntry)
+		if !ok {
+			return m, nil
+		}
+		return m, chooseBaseCmd(r.name)
+
+	case key.Matches(msg, m.keys.MergeBase):
+		r, ok := m.baseList.SelectedItem().(refEntry)
+		if !ok || r.name == "" {
+			return m, nil
+		}
+		return m, chooseBaseCmd(r.name + "...")
+	}
+
+	var cmd tea.Cmd
+	m.baseList, cmd = m.baseList.Update(msg)
+	return m, cmd
+}
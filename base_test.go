@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseRefs(t *testing.T) {
+	cases := []struct {
+		out  string
+		want []string
+	}{
+		{"main\nfeature/x\norigin/HEAD\norigin/main\nv1.0\n", []string{"main", "feature/x", "origin/main", "v1.0"}},
+		{"", nil},
+		{"\n", nil},
+	}
+	for _, c := range cases {
+		if got := parseRefs(c.out); !slices.Equal(got, c.want) {
+			t.Errorf("parseRefs(%q) = %q, want %q", c.out, got, c.want)
+		}
+	}
+
+	items := refItems(parseRefs("main\norigin/HEAD\n"))
+	if len(items) != 2 || items[0].(refEntry).name != "" || items[1].(refEntry).name != "main" {
+		t.Errorf("refItems = %v, want HEAD then main", items)
+	}
+}
+
+func TestResolveBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	old := workDir
+	workDir = dir
+	t.Cleanup(func() { workDir = old })
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	// main: fork → ahead; feature (HEAD): fork → work
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "fork")
+	fork := git("rev-parse", "HEAD")
+	git("commit", "-q", "--allow-empty", "-m", "ahead")
+	ahead := git("rev-parse", "HEAD")
+	git("checkout", "-q", "-b", "feature", fork)
+	git("commit", "-q", "--allow-empty", "-m", "work")
+	git("tag", "v1", fork)
+
+	cases := []struct {
+		base    string
+		want    string
+		wantErr bool
+	}{
+		{"main", ahead, false},
+		{"main...", fork, false}, // merge-base with HEAD
+		{"v1", fork, false},
+		{"main~1", fork, false},
+		{"nope", "", true},
+		{"nope...", "", true},
+	}
+	for _, c := range cases {
+		got, err := resolveBase(c.base)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("resolveBase(%q) = %q, %v; want %q, error %v", c.base, got, err, c.want, c.wantErr)
+		}
+	}
+
+	refs, err := getRefs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"feature", "main", "v1"}; !slices.Equal(refs, want) {
+		t.Errorf("getRefs() = %q, want %q", refs, want)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// blameLine is the commit that last changed one line of a file.
+type blameLine struct {
+	hash    string // all zeros for lines not committed yet
+	author  string
+	when    time.Time
+	summary string
+}
+
+func (b blameLine) uncommitted() bool { return strings.Trim(b.hash, "0") == "" }
+
+type blameLoadedMsg struct {
+	rev, path string
+	lines     []blameLine
+	err       error
+}
+
+// loadBlame blames path as of rev, or the working file when rev is empty.
+func loadBlame(rev, path string) tea.Cmd {
+	return func() tea.Msg {
+		args := []string{"blame", "--porcelain"}
+		if rev != "" {
+			args = append(args, rev)
+		}
+		out, err := gitCmd(append(args, "--", path)...)
+		var exit *exec.ExitError
+		if errors.As(err, &exit) && len(exit.Stderr) > 0 {
+			msg, _, _ := strings.Cut(strings.TrimSpace(string(exit.Stderr)), "\n")
+			// Untracked and newly added files aren't in HEAD: none of their
+			// lines is committed yet
+			if rev == "" && strings.Contains(msg, "no such path") {
+				content, rerr := readFile(path)
+				if rerr == nil {
+					return blameLoadedMsg{path: path, lines: uncommittedBlame(content)}
+				}
+			}
+			err = fmt.Errorf("%s", strings.TrimPrefix(msg, "fatal: "))
+		}
+		return blameLoadedMsg{rev: rev, path: path, lines: parseBlame(out), err: err}
+	}
+}
+
+// uncommittedBlame blames every line of content on the working tree.
+func uncommittedBlame(content string) []blameLine {
+	n := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		n++
+	}
+	lines := make([]blameLine, n)
+	for i := range lines {
+		lines[i].hash = strings.Repeat("0", 40)
+	}
+	return lines
+}
+
+// parseBlame reads `git blame --porcelain` output into one entry per line.
+// A commit's details follow only its first header, so entries share them
+// by hash.
+func parseBlame(out string) []blameLine {
+	commits := map[string]*blameLine{}
+	var lines []blameLine
+	var cur *blameLine
+	final := 0
+	for _, l := range strings.Split(out, "\n") {
+		if strings.HasPrefix(l, "\t") {
+			// The line's content ends its entry
+			if cur != nil && final > 0 {
+				for len(lines) < final {
+					lines = append(lines, blameLine{})
+				}
+				lines[final-1] = *cur
+			}
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			// Header: <hash> <orig line> <final line> [<lines in group>]
+			fields := strings.Fields(l)
+			if len(fields) < 3 || !isHash(fields[0]) {
+				continue
+			}
+			final, _ = strconv.Atoi(fields[2])
+			if commits[fields[0]] == nil {
+				commits[fields[0]] = &blameLine{hash: fields[0]}
+			}
+			cur = commits[fields[0]]
+			continue
+		}
+		k, v, _ := strings.Cut(l, " ")
+		switch k {
+		case "author":
+			cur.author = v
+		case "author-time":
+			if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+				cur.when = time.Unix(sec, 0)
+			}
+		case "summary":
+			cur.summary = v
+		}
+	}
+	return lines
+}
+
+func isHash(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// blameAuthorWidth is how much of the author's name the gutter shows.
+const blameAuthorWidth = 10
+
+// blameGutter renders the blame column for each line: short hash, author
+// and age, shown once per run of lines from the same commit. Uncommitted
+// lines are marked instead.
+func blameGutter(lines []blameLine, now time.Time) []string {
+	const width = 7 + 1 + blameAuthorWidth + 1 + 10 + 1 // hash, author, age as date
+	col := make([]string, len(lines))
+	for i, b := range lines {
+		switch {
+		case i > 0 && b.hash == lines[i-1].hash:
+			col[i] = strings.Repeat(" ", width)
+		case b.hash == "":
+			col[i] = strings.Repeat(" ", width)
+		case b.uncommitted():
+			mark := "● uncommitted"
+			col[i] = dirtyIndicatorStyle.Render(mark + strings.Repeat(" ", width-lipgloss.Width(mark)))
+		default:
+			author := ansi.Truncate(b.author, blameAuthorWidth, "…")
+			author += strings.Repeat(" ", blameAuthorWidth-lipgloss.Width(author))
+			age := strings.TrimSuffix(relativeTime(b.when, now), " ago")
+			col[i] = logHashStyle.Render(b.hash[:7]) + " " + logAuthorStyle.Render(author) + " " +
+				logDateStyle.Render(fmt.Sprintf("%-10s", age)) + " "
+		}
+	}
+	return col
+}
+
+// blameCounter describes the commit behind the line under the cursor.
+func (m model) blameCounter() string {
+	if m.blameFor != m.currentFile || m.cursorLine < 0 || m.cursorLine >= len(m.blameLines) {
+		return ""
+	}
+	b := m.blameLines[m.cursorLine]
+	switch {
+	case b.hash == "":
+		return ""
+	case b.uncommitted():
+		return "not committed yet"
+	}
+	return fmt.Sprintf("%s %s, %s: %s", b.hash[:7], b.author, relativeTime(b.when, time.Now()), b.summary)
+}
+
+// blameShown reports whether the blame column belongs in the viewer now.
+func (m model) blameShown() bool {
+	return m.blame && !m.diffMode && !m.mdPreview && m.travel == nil
+}
+
+// toggleBlame shows or hides the blame column.
+func (m model) toggleBlame() (tea.Model, tea.Cmd) {
+	if !m.blame && (m.diffMode || m.mdPreview || m.travel != nil) {
+		m.setFlash("blame needs the file view", true)
+		return m, nil
+	}
+	m.blame = !m.blame
+	if !m.blame {
+		m.blameLines = nil
+		m.refreshViewport()
+		return m, nil
+	}
+	return m, m.reloadBlame()
+}
+
+// blameRev is the revision the viewer shows: the commit being browsed, or
+// "" for the working tree.
+func (m model) blameRev() string {
+	if m.logCommit != nil {
+		return m.logCommit.hash
+	}
+	return ""
+}
+
+// reloadBlame blames the open file as the viewer shows it.
+func (m model) reloadBlame() tea.Cmd {
+	return loadBlame(m.blameRev(), m.currentFile)
+}
+
+// blameLoaded keeps a blame that still matches the open file.
+func (m model) blameLoaded(msg blameLoadedMsg) (tea.Model, tea.Cmd) {
+	if !m.blame || msg.path != m.currentFile || msg.rev != m.blameRev() {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.blameLines = nil
+		m.setFlash("git blame: "+msg.err.Error(), true)
+	} else {
+		m.blameLines = msg.lines
+	}
+	m.blameFor = msg.path
+	m.refreshViewport()
+	return m, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	blameA = "1111111111111111111111111111111111111111"
+	blameZ = "0000000000000000000000000000000000000000"
+)
+
+func TestParseBlame(t *testing.T) {
+	out := blameA + " 1 1 2\n" +
+		"author Ada Lovelace\n" +
+		"author-time 1700000000\n" +
+		"summary Add engine\n" +
+		"filename f.go\n" +
+		"\tpackage main\n" +
+		blameA + " 2 2\n" +
+		"\t\n" +
+		blameZ + " 3 3 1\n" +
+		"author Not Committed Yet\n" +
+		"summary Version of f.go from f.go\n" +
+		"\tfunc main() {}\n"
+	lines := parseBlame(out)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	// The second line only has a header; it shares the commit's details
+	for i := 0; i < 2; i++ {
+		if b := lines[i]; b.hash != blameA || b.author != "Ada Lovelace" || b.summary != "Add engine" || !b.when.Equal(time.Unix(1700000000, 0)) {
+			t.Errorf("line %d = %+v", i+1, b)
+		}
+	}
+	if !lines[2].uncommitted() || lines[0].uncommitted() {
+		t.Errorf("uncommitted = %v, %v; want the third line only", lines[0].uncommitted(), lines[2].uncommitted())
+	}
+}
+
+func TestBlameGutterOncePerRun(t *testing.T) {
+	when := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	a := blameLine{hash: blameA, author: "Ada Lovelace", when: when}
+	z := blameLine{hash: blameZ}
+	col := blameGutter([]blameLine{a, a, z, a}, when.Add(3*24*time.Hour))
+
+	if got := ansi.Strip(col[0]); !strings.HasPrefix(got, "1111111 Ada Lovel… 3d ") {
+		t.Errorf("first line = %q", got)
+	}
+	if got := ansi.Strip(col[1]); strings.TrimSpace(got) != "" {
+		t.Errorf("repeat of the same commit = %q, want blank", got)
+	}
+	if got := ansi.Strip(col[2]); !strings.Contains(got, "uncommitted") {
+		t.Errorf("uncommitted line = %q", got)
+	}
+	if ansi.Strip(col[3]) != ansi.Strip(col[0]) {
+		t.Errorf("new run = %q, want %q", ansi.Strip(col[3]), ansi.Strip(col[0]))
+	}
+	for i, c := range col {
+		if w := ansi.StringWidth(c); w != ansi.StringWidth(col[0]) {
+			t.Errorf("line %d is %d wide, want %d", i, w, ansi.StringWidth(col[0]))
+		}
+	}
+}
+
+func TestBlameNotInHead(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	old := workDir
+	workDir = dir
+	t.Cleanup(func() { workDir = old })
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "init")
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("a\nb\nc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Untracked, then added
+	for _, step := range []string{"untracked", "added"} {
+		if step == "added" {
+			git("add", "new.txt")
+		}
+		msg := loadBlame("", "new.txt")().(blameLoadedMsg)
+		if msg.err != nil {
+			t.Fatalf("%s: err = %v", step, msg.err)
+		}
+		if len(msg.lines) != 3 {
+			t.Fatalf("%s: got %d lines, want 3", step, len(msg.lines))
+		}
+		for i, b := range msg.lines {
+			if !b.uncommitted() {
+				t.Errorf("%s: line %d = %+v, want uncommitted", step, i+1, b)
+			}
+		}
+	}
+}
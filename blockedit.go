@@ -0,0 +1,344 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// openBlockEdit starts a multi-line quick-fix over the selected lines, or the
+// cursor line plus cfg.Edit.Context lines on either side.
+func (m model) openBlockEdit() (tea.Model, tea.Cmd) {
+	if m.mdPreview {
+		return m, nil
+	}
+	if reason := m.readOnly(); reason != "" {
+		m.setFlash(reason, true)
+		return m, nil
+	}
+
+	from, to, ok := m.blockRange()
+	if !ok {
+		m.setFlash("no lines of the working file here to edit", true)
+		return m, nil
+	}
+	content, err := readFile(m.currentFile)
+	if err != nil {
+		m.setFlash(err.Error(), true)
+		return m, nil
+	}
+	lines := strings.Split(content, "\n")
+	last := len(lines) - 1
+	if last > 0 && lines[last] == "" {
+		last-- // trailing newline, not a line
+	}
+	if !m.visual {
+		from -= cfg.Edit.Context
+		to += cfg.Edit.Context
+	}
+	from, to = max(from, 0), min(to, last)
+	if from > to {
+		return m, nil
+	}
+
+	orig := make([]string, 0, to-from+1)
+	block := make([]string, 0, to-from+1)
+	tabs := false
+	for _, l := range lines[from : to+1] {
+		l = strings.TrimRight(l, "\r")
+		tabs = tabs || strings.HasPrefix(l, "\t")
+		orig = append(orig, l)
+		block = append(block, expandTabs(l))
+	}
+
+	innerW, _ := m.innerSize()
+	ta := textarea.New()
+	ta.ShowLineNumbers = false
+	ta.Prompt = ""
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.SetWidth(innerW - 8) // leave room for the gutter
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle().Background(colorHighlight)
+	ta.SetValue(strings.Join(block, "\n"))
+	// SetValue leaves the cursor at the end; start on the cursor's line
+	cursorAt := m.cursorFileLine() - from
+	for ta.Line() > cursorAt && ta.Line() > 0 {
+		ta.CursorUp()
+	}
+	ta.CursorStart()
+
+	m.blockEdit = true
+	m.blockFrom, m.blockTo = from, to
+	m.fixBase = newEditBase(content, from, orig)
+	m.blockTabs = tabs
+	m.blockRow = m.displayRow(from)
+	m.visual = false
+	m.blockInput = ta
+	m.fitBlockEditor()
+	return m, m.blockInput.Focus()
+}
+
+// blockRange is the span of file lines (0-based, inclusive) under the
+// selection or cursor, before any context is added.
+func (m model) blockRange() (from, to int, ok bool) {
+	if !m.diffMode {
+		sel := m.selection()
+		if sel == noRange {
+			return m.cursorLine, m.cursorLine, true
+		}
+		return sel.from, sel.to, true
+	}
+
+	labels := diffLineNumbers(strings.Split(m.rawContent, "\n"))
+	sel := m.diffSelection()
+	if sel == noRange {
+		n := m.cursorFileLine()
+		return n, n, n >= 0
+	}
+	from, to = -1, -1
+	for i := sel.from; i <= sel.to && i < len(labels); i++ {
+		n, err := strconv.Atoi(labels[i])
+		if err != nil {
+			continue // header, hunk marker or deleted line
+		}
+		if from < 0 {
+			from = n - 1
+		}
+		to = n - 1
+	}
+	return from, to, from >= 0
+}
+
+// cursorFileLine is the 0-based file line under the cursor, or -1 when the
+// cursor is on a diff line that isn't in the working file.
+func (m model) cursorFileLine() int {
+	if !m.diffMode {
+		return m.cursorLine
+	}
+	labels := diffLineNumbers(strings.Split(m.rawContent, "\n"))
+	dl := m.diffLine(m.cursorLine)
+	if dl < 0 || dl >= len(labels) {
+		return -1
+	}
+	n, err := strconv.Atoi(labels[dl])
+	if err != nil {
+		return -1
+	}
+	return n - 1
+}
+
+// displayRow is the viewer line showing 0-based file line n.
+func (m model) displayRow(n int) int {
+	if !m.diffMode {
+		return n
+	}
+	want := strconv.Itoa(n + 1)
+	for i, l := range diffLineNumbers(strings.Split(m.rawContent, "\n")) {
+		if l == want {
+			if m.splitActive() {
+				return rowForLine(m.splitRows(), i)
+			}
+			return i
+		}
+	}
+	return m.cursorLine
+}
+
+// fitBlockEditor grows the editor with its content, leaving a spare line for
+// the next insertion, up to the viewer's height.
+func (m *model) fitBlockEditor() {
+	h := m.blockInput.LineCount() + 1
+	if limit := m.viewport.Height; limit > 0 && h > limit {
+		h = limit
+	}
+	m.blockInput.SetHeight(h)
+}
+
+// updateBlockEdit handles keys in the block editor.
+func (m model) updateBlockEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.blockEdit = false
+		yoff := m.viewport.YOffset
+		m.viewport.SetContent(m.viewportContent())
+		m.viewport.SetYOffset(yoff)
+		return m, nil
+
+	case key.Matches(msg, m.keys.EditSave):
+		var newLines []string
+		if value := m.blockInput.Value(); value != "" {
+			newLines = strings.Split(value, "\n")
+		}
+		newLines = restoreBlock(m.fixBase.lines, newLines, m.blockTabs)
+		m.blockEdit = false
+		m.quickFixPending = true
+		m.quickFixYOffset = m.viewport.YOffset
+		m.loadSeq++
+		item, _ := m.list.SelectedItem().(fileEntry)
+		return m, writeAndReloadCmd(m.currentFile, &m.fixBase, m.blockFrom, m.blockTo, newLines, m.loadContent(m.currentFile, item.status))
+
+	case key.Matches(msg, m.keys.EditInsertLine):
+		// Open a line below with the current line's indentation
+		line := m.blockLine()
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		m.blockInput.CursorEnd()
+		m.blockInput.InsertString("\n" + indent)
+		m.fitBlockEditor()
+		return m, nil
+
+	case key.Matches(msg, m.keys.EditDeleteLine):
+		row := m.blockInput.Line()
+		lines := strings.Split(m.blockInput.Value(), "\n")
+		lines = append(lines[:row], lines[row+1:]...)
+		m.blockInput.SetValue(strings.Join(lines, "\n"))
+		for m.blockInput.Line() > row {
+			m.blockInput.CursorUp()
+		}
+		m.blockInput.CursorStart()
+		m.fitBlockEditor()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.blockInput, cmd = m.blockInput.Update(msg)
+	m.fitBlockEditor()
+	return m, cmd
+}
+
+// blockLine is the editor line holding the cursor.
+func (m model) blockLine() string {
+	lines := strings.Split(m.blockInput.Value(), "\n")
+	if row := m.blockInput.Line(); row < len(lines) {
+		return lines[row]
+	}
+	return ""
+}
+
+// expandTabs shows each tab as four spaces, since the editor can't lay out
+// real tabs.
+func expandTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", "    ")
+}
+
+// restoreBlock maps the edited block back onto the original lines. Lines the
+// edit left alone keep their original bytes; a changed line keeps the
+// original bytes of its untouched start and end, so tabs there survive. New
+// lines get tab indentation when the block used it.
+func restoreBlock(orig, edited []string, tabs bool) []string {
+	// lcs[i][j] is the common subsequence length of orig[i:] and edited[j:]
+	lcs := make([][]int, len(orig)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(edited)+1)
+	}
+	for i := len(orig) - 1; i >= 0; i-- {
+		for j := len(edited) - 1; j >= 0; j-- {
+			if expandTabs(orig[i]) == edited[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	out := make([]string, 0, len(edited))
+	var removed []string // original lines replaced since the last unchanged one
+	for i, j := 0, 0; j < len(edited); {
+		switch {
+		case i < len(orig) && expandTabs(orig[i]) == edited[j]:
+			out = append(out, orig[i])
+			removed = removed[:0]
+			i++
+			j++
+		case i < len(orig) && lcs[i+1][j] >= lcs[i][j+1]:
+			removed = append(removed, orig[i])
+			i++
+		default:
+			// Pair a changed line with the original it most likely replaced
+			if len(removed) > 0 {
+				out = append(out, unexpandLine(removed[0], edited[j]))
+				removed = removed[1:]
+			} else if tabs {
+				out = append(out, restoreTabs(edited[j]))
+			} else {
+				out = append(out, edited[j])
+			}
+			j++
+		}
+	}
+	return out
+}
+
+// unexpandLine puts back the original bytes of the start and end of edited
+// that match orig's expanded form, leaving what was typed in between as is.
+func unexpandLine(orig, edited string) string {
+	// pos[i] is where orig[i:] starts in the expanded line
+	pos := make([]int, len(orig)+1)
+	for i := range len(orig) {
+		pos[i+1] = pos[i] + 1
+		if orig[i] == '\t' {
+			pos[i+1] = pos[i] + 4
+		}
+	}
+	exp := expandTabs(orig)
+
+	prefix := 0
+	for prefix < len(exp) && prefix < len(edited) && exp[prefix] == edited[prefix] {
+		prefix++
+	}
+	// Cut before a tab the prefix only partly covers
+	from := 0
+	for from < len(orig) && pos[from+1] <= prefix {
+		from++
+	}
+	prefix = pos[from]
+
+	suffix := 0
+	for suffix < len(exp)-prefix && suffix < len(edited)-prefix &&
+		exp[len(exp)-1-suffix] == edited[len(edited)-1-suffix] {
+		suffix++
+	}
+	to := len(orig)
+	for to > from && len(exp)-pos[to-1] <= suffix {
+		to--
+	}
+	suffix = len(exp) - pos[to]
+
+	return orig[:from] + edited[prefix:len(edited)-suffix] + orig[to:]
+}
+
+// restoreTabs turns the leading four-space groups of a new line into tabs,
+// matching a tab-indented block.
+func restoreTabs(line string) string {
+	n := 0
+	for strings.HasPrefix(line[n*4:], "    ") {
+		n++
+	}
+	return strings.Repeat("\t", n) + line[n*4:]
+}
+
+// overlayBlockEditor draws the block editor over the viewer lines it edits,
+// moving it up if it would run off the bottom.
+func (m model) overlayBlockEditor(viewContent string) string {
+	vcLines := strings.Split(viewContent, "\n")
+	edLines := strings.Split(m.blockInput.View(), "\n")
+	top := m.blockRow - m.viewport.YOffset
+	if top+len(edLines) > len(vcLines) {
+		top = len(vcLines) - len(edLines)
+	}
+	top = max(top, 0)
+
+	maxLabel := max(len(strconv.Itoa(m.blockTo+1)), 3)
+	gutter := lineNumStyle.Width(maxLabel).Render("") + dirtyIndicatorStyle.Render("┃") + " "
+	for i, l := range edLines {
+		if top+i >= len(vcLines) {
+			break
+		}
+		vcLines[top+i] = ansi.Truncate(gutter+l, m.viewport.Width, "")
+	}
+	return strings.Join(vcLines, "\n")
+}
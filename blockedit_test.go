@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReplaceFileLines(t *testing.T) {
+	dir := t.TempDir()
+	old := workDir
+	workDir = dir
+	t.Cleanup(func() { workDir = old })
+
+	path := filepath.Join(dir, "f.txt")
+	if err := os.WriteFile(path, []byte("a\r\nb\r\nc\r\nd\r\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	before, err := replaceFileLines("f.txt", 1, 2, []string{"B", "C", "X"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"b", "c"}; !slices.Equal(before, want) {
+		t.Errorf("replaced = %q, want %q", before, want)
+	}
+	data, _ := os.ReadFile(path)
+	if got, want := string(data), "a\r\nB\r\nC\r\nX\r\nd\r\n"; got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o755 {
+		t.Errorf("mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	// Deleting the whole block
+	if _, err := replaceFileLines("f.txt", 1, 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if got, want := string(data), "a\r\nd\r\n"; got != want {
+		t.Errorf("after delete = %q, want %q", got, want)
+	}
+
+	if _, err := replaceFileLines("f.txt", 2, 9, nil); err == nil {
+		t.Error("out of range block: want error")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
+
+func TestRestoreTabs(t *testing.T) {
+	cases := map[string]string{
+		"        x := 1": "\t\tx := 1",
+		"      y":        "\t  y",
+		"z    ":          "z    ",
+		"":               "",
+	}
+	for in, want := range cases {
+		if got := restoreTabs(in); got != want {
+			t.Errorf("restoreTabs(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRestoreBlock(t *testing.T) {
+	orig := []string{
+		"\tx := 1\t// one",
+		"  spaced",
+		"a\tb\tc",
+		"\ty := 2\t// two",
+	}
+	edited := make([]string, len(orig))
+	for i, l := range orig {
+		edited[i] = expandTabs(l)
+	}
+	if got := restoreBlock(orig, edited, true); !slices.Equal(got, orig) {
+		t.Errorf("untouched block = %q, want %q", got, orig)
+	}
+
+	edited[3] = "    y := 3    // two"        // edit between the tabs
+	edited = append(edited, "        z := 4") // new line
+	want := []string{
+		"\tx := 1\t// one",
+		"  spaced",
+		"a\tb\tc",
+		"\ty := 3\t// two",
+		"\t\tz := 4",
+	}
+	if got := restoreBlock(orig, edited, true); !slices.Equal(got, want) {
+		t.Errorf("edited block = %q, want %q", got, want)
+	}
+
+	// A deleted line, and a line rewritten across a tab
+	edited = []string{"    x := 1    // one", "a b c", "    y := 2    // two"}
+	want = []string{"\tx := 1\t// one", "a b c", "\ty := 2\t// two"}
+	if got := restoreBlock(orig, edited, false); !slices.Equal(got, want) {
+		t.Errorf("rewritten block = %q, want %q", got, want)
+	}
+}
+
+func TestWriteFileAtomicThroughSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real.txt")
+	link := filepath.Join(dir, "link.txt")
+	if err := os.WriteFile(target, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("real.txt", link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := writeFileAtomic(link, []byte("new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("link replaced by a regular file")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "new\n" {
+		t.Errorf("target = %q, want the new content", data)
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Conventional commit message limits.
+const (
+	subjectSoftLimit = 50
+	subjectHardLimit = 72
+	bodyWrapLimit    = 72
+)
+
+type commitFilesMsg struct {
+	files []fileEntry
+	err   error
+}
+
+type commitDoneMsg struct {
+	summary string // "abc1234 subject" on success
+	err     error
+}
+
+type lastCommitMsg struct {
+	message string
+}
+
+// commitLint is one inline rule violation for the message being composed.
+// Only an empty subject is an error; the conventions are warnings.
+type commitLint struct {
+	isErr bool // errors block the commit, warnings don't
+	text  string
+}
+
+// lintCommitMessage checks msg against the usual subject/body conventions.
+func lintCommitMessage(msg string) []commitLint {
+	var lints []commitLint
+	lines := strings.Split(strings.TrimRight(msg, "\n"), "\n")
+	subject := lines[0]
+
+	switch n := len([]rune(subject)); {
+	case strings.TrimSpace(subject) == "":
+		lints = append(lints, commitLint{isErr: true, text: "subject is empty"})
+	case n > subjectHardLimit:
+		lints = append(lints, commitLint{text: fmt.Sprintf("subject is %d chars (max %d)", n, subjectHardLimit)})
+	case n > subjectSoftLimit:
+		lints = append(lints, commitLint{text: fmt.Sprintf("subject is %d chars (aim for %d)", n, subjectSoftLimit)})
+	}
+	if strings.HasSuffix(strings.TrimSpace(subject), ".") {
+		lints = append(lints, commitLint{text: "subject ends with a period"})
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		lints = append(lints, commitLint{text: "line 2 should be blank"})
+	}
+	for i := 2; i < len(lines); i++ {
+		if n := len([]rune(lines[i])); n > bodyWrapLimit {
+			lints = append(lints, commitLint{text: fmt.Sprintf("line %d is %d chars (wrap at %d)", i+1, n, bodyWrapLimit)})
+		}
+	}
+	return lints
+}
+
+// loadCommitFiles fetches the staged files for the composer.
+func loadCommitFiles() tea.Cmd {
+	return func() tea.Msg {
+		files, err := getChangedFiles("")
+		return commitFilesMsg{files: stagedFiles(files), err: err}
+	}
+}
+
+// stagedFiles filters files down to those with changes in the index.
+func stagedFiles(files []fileEntry) []fileEntry {
+	var staged []fileEntry
+	for _, f := range files {
+		if f.hasStaged() {
+			staged = append(staged, f)
+		}
+	}
+	return staged
+}
+
+func loadLastCommitMessage() tea.Cmd {
+	return func() tea.Msg {
+		out, err := gitCmd("log", "-1", "--format=%B")
+		if err != nil {
+			return lastCommitMsg{}
+		}
+		return lastCommitMsg{message: strings.TrimRight(out, "\n")}
+	}
+}
+
+func runCommitCmd(message string, amend, signoff bool) tea.Cmd {
+	return func() tea.Msg {
+		summary, err := gitCommit(message, amend, signoff)
+		return commitDoneMsg{summary: summary, err: err}
+	}
+}
+
+// openCommit switches to the commit composer.
+func (m model) openCommit() (tea.Model, tea.Cmd) {
+	innerW, innerH := m.innerSize()
+	ta := textarea.New()
+	ta.Placeholder = "Subject line\n\nWhy this change was made…"
+	ta.ShowLineNumbers = false
+	ta.Prompt = ""
+	ta.CharLimit = 0
+	ta.SetWidth(innerW - 2)
+	ta.SetHeight(commitEditorHeight(innerH))
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle().Background(colorHighlight)
+	ta.FocusedStyle.Placeholder = lipgloss.NewStyle().Foreground(colorFgDim)
+
+	m.commitPrevView = m.currentView
+	m.currentView = commitView
+	m.commitInput = ta
+	m.commitAmend = false
+	m.commitSignoff = false
+	m.commitFiles = nil
+	return m, tea.Batch(m.commitInput.Focus(), loadCommitFiles())
+}
+
+// commitEditorHeight leaves room for the title, file list and lint lines.
+func commitEditorHeight(innerH int) int {
+	h := innerH - 12
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+// updateCommit handles keys in the commit composer.
+func (m model) updateCommit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.currentView = m.commitPrevView
+		return m, nil
+
+	case key.Matches(msg, m.keys.CommitAmend):
+		m.commitAmend = !m.commitAmend
+		if m.commitAmend && strings.TrimSpace(m.commitInput.Value()) == "" {
+			return m, loadLastCommitMessage()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.CommitSignoff):
+		m.commitSignoff = !m.commitSignoff
+		return m, nil
+
+	case key.Matches(msg, m.keys.CommitSubmit):
+		message := m.commitInput.Value()
+		for _, l := range lintCommitMessage(message) {
+			if l.isErr {
+				m.setFlash(l.text, true)
+				return m, nil
+			}
+		}
+		if len(m.commitFiles) == 0 && !m.commitAmend {
+			m.setFlash("nothing staged to commit", true)
+			return m, nil
+		}
+		return m, runCommitCmd(message, m.commitAmend, m.commitSignoff)
+	}
+
+	var cmd tea.Cmd
+	m.commitInput, cmd = m.commitInput.Update(msg)
+	return m, cmd
+}
+
+// renderCommit draws the composer: toggles, staged files, editor and lint results.
+func (m model) renderCommit(width, height int) string {
+	title := breadcrumbFileStyle.Render("Commit")
+	if m.commitAmend {
+		title += " " + fixBadgeStyle.Render("AMEND")
+	}
+	if m.commitSignoff {
+		title += " " + stagedBadgeStyle.Render("SIGNOFF")
+	}
+	subject := strings.SplitN(m.commitInput.Value(), "\n", 2)[0]
+	counter := fmt.Sprintf("%d/%d", len([]rune(subject)), subjectSoftLimit)
+	counterStyle := scrollPctStyle
+	if len([]rune(subject)) > subjectSoftLimit {
+		counterStyle = dirtyIndicatorStyle
+	}
+	gap := width - lipgloss.Width(title) - len(counter)
+	if gap < 1 {
+		gap = 1
+	}
+	lines := []string{title + strings.Repeat(" ", gap) + counterStyle.Render(counter)}
+
+	// Staged files, capped so the editor keeps its space
+	const maxFiles = 4
+	lines = append(lines, helpSectionStyle.Render(fmt.Sprintf("Staged (%d)", len(m.commitFiles))))
+	if len(m.commitFiles) == 0 {
+		lines = append(lines, headerDimStyle.Render("  nothing staged"))
+	}
+	for i, f := range m.commitFiles {
+		if i == maxFiles {
+			lines = append(lines, headerDimStyle.Render(fmt.Sprintf("  … %d more", len(m.commitFiles)-maxFiles)))
+			break
+		}
+		badge := statusBadgeStyle(string(f.index)).Render(statusLabel(string(f.index)))
+		lines = append(lines, "  "+badge+" "+pathFileStyle.Render(f.path))
+	}
+	for len(lines) < maxFiles+3 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, separatorStyle.Render(strings.Repeat("─", width)))
+	lines = append(lines, m.commitInput.View())
+	lines = append(lines, separatorStyle.Render(strings.Repeat("─", width)))
+
+	lints := lintCommitMessage(m.commitInput.Value())
+	if len(lints) == 0 {
+		lines = append(lines, flashStyle.Render("✓ message looks good"))
+	}
+	for _, l := range lints {
+		if l.isErr {
+			lines = append(lines, flashErrStyle.Render("✗ "+l.text))
+		} else {
+			lines = append(lines, dirtyIndicatorStyle.Render("! "+l.text))
+		}
+	}
+
+	out := strings.Join(lines, "\n")
+	outLines := strings.Split(out, "\n")
+	if len(outLines) > height {
+		outLines = outLines[:height]
+	}
+	return strings.Join(outLines, "\n")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintCommitMessage(t *testing.T) {
+	cases := []struct {
+		name     string
+		msg      string
+		wantErr  bool
+		wantText string // substring expected in some lint, "" for none
+	}{
+		{"clean", "Fix race in watcher\n\nThe index lock was read too early.", false, ""},
+		{"empty", "", true, "subject is empty"},
+		{"long subject warns", strings.Repeat("x", 60), false, "aim for 50"},
+		{"too long subject warns", strings.Repeat("x", 80), false, "max 72"},
+		{"period", "Fix the thing.", false, "period"},
+		{"missing blank line warns", "Subject\nbody starts here", false, "line 2 should be blank"},
+		{"long body line", "Subject\n\n" + strings.Repeat("y", 80), false, "line 3 is 80 chars"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lints := lintCommitMessage(tc.msg)
+			gotErr := false
+			found := tc.wantText == ""
+			for _, l := range lints {
+				if l.isErr {
+					gotErr = true
+				}
+				if tc.wantText != "" && strings.Contains(l.text, tc.wantText) {
+					found = true
+				}
+			}
+			if gotErr != tc.wantErr {
+				t.Errorf("error = %v, want %v (lints: %+v)", gotErr, tc.wantErr, lints)
+			}
+			if !found {
+				t.Errorf("no lint containing %q (lints: %+v)", tc.wantText, lints)
+			}
+			if tc.wantText == "" && len(lints) != 0 {
+				t.Errorf("expected no lints, got %+v", lints)
+			}
+		})
+	}
+}
+
+func TestStagedFiles(t *testing.T) {
+	files := parsePorcelain("M  a.go\n M b.go\nMM c.go\n?? d.go\n")
+	staged := stagedFiles(files)
+	if len(staged) != 2 || staged[0].path != "a.go" || staged[1].path != "c.go" {
+		t.Errorf("stagedFiles = %+v, want a.go and c.go", staged)
+	}
+}
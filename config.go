@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/chroma/v2/styles"
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// repoConfigName is the optional per-repo config file at the repo root.
+const repoConfigName = ".git-owl.toml"
+
+// cfg is the active configuration, loaded in main.
+var cfg = defaultConfig()
+
+// config is loaded from (lowest to highest priority) each XDG config dir,
+// the XDG config home, and the repo's .git-owl.toml. Later files only
+// override the settings they mention.
+type config struct {
+	Startup   startupConfig       `toml:"startup"`
+	Refresh   refreshConfig       `toml:"refresh"`
+	Theme     themeConfig         `toml:"theme"`
+	Highlight highlightConfig     `toml:"highlight"`
+	Edit      editConfig          `toml:"edit"`
+	Snapshots snapshotsConfig     `toml:"snapshots"`
+	Keys      map[string][]string `toml:"keys"`
+}
+
+type startupConfig struct {
+	Diff  bool   `toml:"diff"`  // open files in diff mode
+	Split bool   `toml:"split"` // show diffs side by side
+	Tree  bool   `toml:"tree"`  // start in the all-files tree instead of changed files
+	Sort  string `toml:"sort"`  // order of the changed files
+	Group string `toml:"group"` // sections of the changed files
+}
+
+type refreshConfig struct {
+	Watch      bool     `toml:"watch"`       // use the filesystem watcher when available
+	Poll       duration `toml:"poll"`        // poll interval without a watcher
+	SafetyPoll duration `toml:"safety_poll"` // poll interval while watching
+	Debounce   duration `toml:"debounce"`    // quiet period before a watcher refresh
+}
+
+type themeConfig struct {
+	Name string `toml:"name"` // built-in theme or a file in the themes dir
+}
+
+type highlightConfig struct {
+	Style string `toml:"style"` // chroma style name, overriding the theme's
+}
+
+type editConfig struct {
+	Context int `toml:"context"` // lines above and below the cursor in a block edit
+}
+
+type snapshotsConfig struct {
+	Enabled bool `toml:"enabled"` // keep each changed file's content as refreshes see it
+}
+
+// duration reads Go duration strings ("2s", "150ms") from TOML.
+type duration struct {
+	time.Duration
+}
+
+func (d *duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	if v <= 0 {
+		return fmt.Errorf("duration must be positive, got %s", b)
+	}
+	d.Duration = v
+	return nil
+}
+
+func defaultConfig() config {
+	return config{
+		Startup: startupConfig{Sort: "path", Group: "none"},
+		Refresh: refreshConfig{
+			Watch:      true,
+			Poll:       duration{2 * time.Second},
+			SafetyPoll: duration{30 * time.Second},
+			Debounce:   duration{150 * time.Millisecond},
+		},
+		Theme: themeConfig{Name: defaultThemeName},
+		Edit:  editConfig{Context: 2},
+	}
+}
+
+// configPaths lists candidate config files in load order.
+func configPaths(repoRoot string) []string {
+	var paths []string
+
+	dirs := os.Getenv("XDG_CONFIG_DIRS")
+	if dirs == "" {
+		dirs = "/etc/xdg"
+	}
+	list := filepath.SplitList(dirs)
+	// XDG_CONFIG_DIRS is most-important-first; load it in reverse
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i] != "" {
+			paths = append(paths, filepath.Join(list[i], "git-owl", "config.toml"))
+		}
+	}
+
+	if home := configHome(); home != "" {
+		paths = append(paths, filepath.Join(home, "git-owl", "config.toml"))
+	}
+
+	if repoRoot != "" {
+		paths = append(paths, filepath.Join(repoRoot, repoConfigName))
+	}
+	return paths
+}
+
+// configHome is $XDG_CONFIG_HOME, defaulting to ~/.config.
+func configHome() string {
+	if home := os.Getenv("XDG_CONFIG_HOME"); home != "" {
+		return home
+	}
+	if h, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(h, ".config")
+	}
+	return ""
+}
+
+// loadConfig layers every config file that exists over the defaults.
+func loadConfig(repoRoot string) (config, error) {
+	c := defaultConfig()
+	for _, path := range configPaths(repoRoot) {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return c, err
+		}
+		if err := decodeConfig(string(data), &c); err != nil {
+			return c, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// decodeConfig merges TOML text into c, rejecting unknown settings so typos
+// don't silently do nothing.
+func decodeConfig(text string, c *config) error {
+	dec := toml.NewDecoder(strings.NewReader(text))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		var strict *toml.StrictMissingError
+		if errors.As(err, &strict) {
+			return fmt.Errorf("unknown setting:\n%s", strict.String())
+		}
+		return err
+	}
+	if c.Highlight.Style != "" {
+		if _, ok := styles.Registry[c.Highlight.Style]; !ok {
+			return fmt.Errorf("highlight.style: unknown chroma style %q", c.Highlight.Style)
+		}
+	}
+	if _, err := parseSortMode(c.Startup.Sort); err != nil {
+		return fmt.Errorf("startup.sort: %w", err)
+	}
+	if _, err := parseGroupMode(c.Startup.Group); err != nil {
+		return fmt.Errorf("startup.group: %w", err)
+	}
+	if c.Edit.Context < 0 {
+		return fmt.Errorf("edit.context must not be negative, got %d", c.Edit.Context)
+	}
+	k := defaultKeyMap()
+	return k.apply(c.Keys)
+}
+
+// keyMap builds the bindings from the defaults plus the [keys] overrides.
+func (c config) keyMap() keyMap {
+	k := defaultKeyMap()
+	// Already validated by decodeConfig
+	_ = k.apply(c.Keys)
+	return k
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeConfigMerges(t *testing.T) {
+	c := defaultConfig()
+	if err := decodeConfig("[refresh]\npoll = \"5s\"\n", &c); err != nil {
+		t.Fatal(err)
+	}
+	if err := decodeConfig("[startup]\ndiff = true\n", &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Refresh.Poll.Duration != 5*time.Second {
+		t.Errorf("poll = %v, want 5s", c.Refresh.Poll.Duration)
+	}
+	if !c.Startup.Diff {
+		t.Error("startup.diff not set")
+	}
+	// Untouched settings keep their defaults
+	if c.Refresh.Debounce.Duration != 150*time.Millisecond || !c.Refresh.Watch {
+		t.Errorf("defaults lost: %+v", c.Refresh)
+	}
+}
+
+func TestDecodeConfigRejectsUnknown(t *testing.T) {
+	for _, text := range []string{
+		"[refresh]\npoll_interval = \"5s\"\n",
+		"[keys]\nstage = [\"a\"]\n",
+		"[refresh]\npoll = \"soon\"\n",
+	} {
+		c := defaultConfig()
+		if err := decodeConfig(text, &c); err == nil {
+			t.Errorf("expected error for %q", text)
+		}
+	}
+}
+
+func TestConfigKeyOverrides(t *testing.T) {
+	c := defaultConfig()
+	text := "[keys]\nstage_hunk = [\"a\", \"A\"]\ndiscard_hunk = []\n"
+	if err := decodeConfig(text, &c); err != nil {
+		t.Fatal(err)
+	}
+	k := c.keyMap()
+	if got := keyHelp(k.StageHunk); got != "a/A" {
+		t.Errorf("stage keys = %q, want a/A", got)
+	}
+	if k.DiscardHunk.Enabled() {
+		t.Error("discard_hunk should be disabled")
+	}
+	if got := keyHelp(k.DiscardHunk); got != "" {
+		t.Errorf("disabled binding shows %q in help", got)
+	}
+	if got := keyHelp(k.Down, k.Up); got != "j/k/↓/↑" {
+		t.Errorf("keyHelp(down, up) = %q", got)
+	}
+}
+
+func TestLoadConfigLayers(t *testing.T) {
+	home := t.TempDir()
+	repo := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("XDG_CONFIG_DIRS", t.TempDir())
+
+	if err := os.MkdirAll(filepath.Join(home, "git-owl"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, text string) {
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(home, "git-owl", "config.toml"), "[highlight]\nstyle = \"dracula\"\n[startup]\ntree = true\n")
+	write(filepath.Join(repo, repoConfigName), "[highlight]\nstyle = \"github\"\n")
+
+	c, err := loadConfig(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Highlight.Style != "github" || !c.Startup.Tree {
+		t.Errorf("got %+v / %+v", c.Highlight, c.Startup)
+	}
+
+	write(filepath.Join(repo, repoConfigName), "bogus = 1\n")
+	if _, err := loadConfig(repo); err == nil || !strings.Contains(err.Error(), repoConfigName) {
+		t.Errorf("error should name the file, got %v", err)
+	}
+}
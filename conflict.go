@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// editBase is what a quick fix started from: a hash of the whole file and
+// the lines being replaced, captured when the editor opened.
+type editBase struct {
+	hash  [sha256.Size]byte
+	from  int
+	lines []string
+}
+
+func newEditBase(content string, from int, lines []string) editBase {
+	return editBase{hash: sha256.Sum256([]byte(content)), from: from, lines: lines}
+}
+
+// editConflict means the lines a quick fix replaces were changed on disk
+// while it was being typed.
+type editConflict struct {
+	path     string
+	from, to int
+}
+
+func (e *editConflict) Error() string {
+	if e.from == e.to {
+		return fmt.Sprintf("%s:%d changed on disk while you were editing", e.path, e.from+1)
+	}
+	return fmt.Sprintf("%s:%d-%d changed on disk while you were editing", e.path, e.from+1, e.to+1)
+}
+
+// locate finds where the base lines are now. An unchanged file, or one
+// changed only elsewhere, merges cleanly: the base lines are still at from,
+// or appear exactly once after lines were added or removed around them.
+// Otherwise the edit conflicts with what's on disk.
+func (b editBase) locate(path string, data []byte, lines []string) (from, to int, err error) {
+	to = b.from + len(b.lines) - 1
+	if sha256.Sum256(data) == b.hash {
+		return b.from, to, nil
+	}
+	if to < len(lines) && slices.Equal(lines[b.from:to+1], b.lines) {
+		return b.from, to, nil
+	}
+	at := -1
+	for i := 0; i+len(b.lines) <= len(lines); i++ {
+		if slices.Equal(lines[i:i+len(b.lines)], b.lines) {
+			if at >= 0 {
+				at = -1 // ambiguous: the block appears more than once
+				break
+			}
+			at = i
+		}
+	}
+	if at < 0 || len(b.lines) == 0 || strings.TrimSpace(strings.Join(b.lines, "")) == "" {
+		return 0, 0, &editConflict{path: path, from: b.from, to: to}
+	}
+	return at, at + len(b.lines) - 1, nil
+}
+
+// saveQuickFix replaces the base lines of path with newLines wherever they
+// are now. With a nil base the lines at from..to are overwritten as is.
+func saveQuickFix(path string, base *editBase, from, to int, newLines []string) (old []string, at int, err error) {
+	locate := func(data []byte, lines []string) (int, int, error) {
+		if base == nil {
+			return from, to, nil
+		}
+		return base.locate(path, data, lines)
+	}
+	return spliceFile(path, locate, newLines)
+}
+
+// confirmOverwrite asks whether to write a conflicting quick fix over the
+// lines that changed on disk.
+func (m *model) confirmOverwrite(c *editConflict, newLines []string) {
+	reload := m.reloadViewer()
+	m.confirm = &confirmPrompt{
+		text:  c.Error() + " — overwrite with your edit",
+		onYes: writeAndReloadCmd(c.path, nil, c.from, c.to, newLines, reload),
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEditBaseLocate(t *testing.T) {
+	orig := "a\nb\nc\nd\n"
+	base := newEditBase(orig, 1, []string{"b"})
+
+	cases := []struct {
+		name    string
+		now     string
+		from    int
+		wantErr bool
+	}{
+		{"unchanged", orig, 1, false},
+		{"changed elsewhere", "a\nb\nc\nD\n", 1, false},
+		{"lines inserted above", "x\ny\na\nb\nc\nd\n", 3, false},
+		{"line itself changed", "a\nB\nc\nd\n", 0, true},
+		{"ambiguous", "b\na\nc\nb\n", 0, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := base.locate("f.txt", []byte(tc.now), strings.Split(tc.now, "\n"))
+			var conflict *editConflict
+			if tc.wantErr {
+				if !errors.As(err, &conflict) {
+					t.Fatalf("err = %v, want conflict", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from != tc.from || to != tc.from {
+				t.Errorf("located at %d-%d, want %d", from, to, tc.from)
+			}
+		})
+	}
+}
+
+func TestSaveQuickFixConflict(t *testing.T) {
+	dir := t.TempDir()
+	old := workDir
+	workDir = dir
+	t.Cleanup(func() { workDir = old })
+
+	path := filepath.Join(dir, "f.txt")
+	os.WriteFile(path, []byte("a\nb\nc\n"), 0o644)
+	base := newEditBase("a\nb\nc\n", 1, []string{"b"})
+
+	// The agent rewrote the line being edited
+	os.WriteFile(path, []byte("a\nbee\nc\n"), 0o644)
+	if _, _, err := saveQuickFix("f.txt", &base, 1, 1, []string{"B"}); err == nil {
+		t.Fatal("want conflict error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "a\nbee\nc\n" {
+		t.Errorf("conflicting save wrote the file: %q", data)
+	}
+
+	// Overwriting anyway ignores the base
+	if _, _, err := saveQuickFix("f.txt", nil, 1, 1, []string{"B"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "a\nB\nc\n" {
+		t.Errorf("overwrite = %q", data)
+	}
+}
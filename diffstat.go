@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statBarWidth is how many cells the file list's churn bars span.
+const statBarWidth = 5
+
+// renderStat formats a diffstat as colored "+N -M" counts.
+func renderStat(st diffStat, bg lipgloss.TerminalColor) string {
+	added, deleted, dim := statAddedStyle, statDeletedStyle, headerDimStyle
+	if bg != nil {
+		added, deleted, dim = added.Background(bg), deleted.Background(bg), dim.Background(bg)
+	}
+	if st.binary {
+		return dim.Render("bin")
+	}
+	return added.Render(fmt.Sprintf("+%d", st.added)) + dim.Render(" ") + deleted.Render(fmt.Sprintf("-%d", st.deleted))
+}
+
+// statBar splits churn into added and deleted cells of a bar scaled so the
+// file with the most churn fills it. Any change gets at least one cell.
+func statBar(st diffStat, most int) (added, deleted int) {
+	churn := st.added + st.deleted
+	if churn == 0 || most == 0 {
+		return 0, 0
+	}
+	cells := max((churn*statBarWidth+most-1)/most, 1)
+	added = (st.added*cells + churn/2) / churn
+	if st.added > 0 && added == 0 {
+		added = 1
+	}
+	if st.deleted > 0 && added == cells && cells > 1 {
+		added--
+	}
+	return added, cells - added
+}
+
+// renderStatBar draws statBar as green and red cells padded to statBarWidth.
+func renderStatBar(st diffStat, most int, bg lipgloss.TerminalColor) string {
+	added, deleted := statBar(st, most)
+	addStyle, delStyle, dim := statAddedStyle, statDeletedStyle, emptyColumnStyle
+	if bg != nil {
+		addStyle, delStyle, dim = addStyle.Background(bg), delStyle.Background(bg), dim.Background(bg)
+	}
+	return addStyle.Render(strings.Repeat("■", added)) + delStyle.Render(strings.Repeat("■", deleted)) +
+		dim.Render(strings.Repeat("·", statBarWidth-added-deleted))
+}
+
+// listStats sums the diffstats of the files in items and finds the largest
+// churn of any one of them.
+func listStats(items []list.Item) (total diffStat, most int) {
+	for _, it := range items {
+		f, ok := it.(fileEntry)
+		if !ok {
+			continue
+		}
+		total.added += f.stat.added
+		total.deleted += f.stat.deleted
+		most = max(most, f.stat.added+f.stat.deleted)
+	}
+	return total, most
+}
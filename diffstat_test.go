@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestStatBar(t *testing.T) {
+	tests := []struct {
+		st             diffStat
+		most           int
+		added, deleted int
+	}{
+		{diffStat{added: 10}, 10, 5, 0},
+		{diffStat{added: 6, deleted: 4}, 10, 3, 2},
+		{diffStat{added: 1}, 100, 1, 0},              // any change shows
+		{diffStat{added: 99, deleted: 1}, 100, 4, 1}, // a lone deletion still gets a cell
+		{diffStat{added: 1, deleted: 40}, 41, 1, 4},  // so does a lone addition
+		{diffStat{}, 10, 0, 0},
+		{diffStat{binary: true}, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		a, d := statBar(tt.st, tt.most)
+		if a != tt.added || d != tt.deleted {
+			t.Errorf("statBar(%+v, %d) = %d, %d; want %d, %d", tt.st, tt.most, a, d, tt.added, tt.deleted)
+		}
+		if a+d > statBarWidth {
+			t.Errorf("statBar(%+v, %d) overflows: %d cells", tt.st, tt.most, a+d)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorClosedMsg is sent when the external editor exits.
+type editorClosedMsg struct {
+	err error
+}
+
+// editorCommand builds the argv that opens path at 1-based line in editor,
+// which may carry its own arguments ("code --wait"). Most editors take
+// +line; the GUI ones and helix want path:line instead.
+func editorCommand(editor, path string, line int) []string {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	if line < 1 {
+		return append(args, path)
+	}
+	switch strings.TrimSuffix(filepath.Base(args[0]), ".exe") {
+	case "code", "code-insiders", "codium", "cursor":
+		return append(args, "--goto", fmt.Sprintf("%s:%d", path, line))
+	case "subl", "zed", "hx", "helix":
+		return append(args, fmt.Sprintf("%s:%d", path, line))
+	default:
+		return append(args, fmt.Sprintf("+%d", line), path)
+	}
+}
+
+// openInEditor suspends the TUI and opens the current file in $VISUAL or
+// $EDITOR at the line under the cursor.
+func (m model) openInEditor() (tea.Model, tea.Cmd) {
+	if m.currentFile == "" {
+		return m, nil
+	}
+	if reason := m.readOnly(); reason != "" {
+		m.setFlash(reason, true)
+		return m, nil
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	// Rendered markdown has no line mapping; a deleted diff line has no line
+	line := 0
+	if !m.mdPreview {
+		line = m.cursorFileLine() + 1
+	}
+	argv := editorCommand(editor, filepath.Join(workDir, m.currentFile), line)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = workDir
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorClosedMsg{err: err}
+	})
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	cases := []struct {
+		editor string
+		line   int
+		want   []string
+	}{
+		{"nvim", 12, []string{"nvim", "+12", "/r/a.go"}},
+		{"", 3, []string{"vi", "+3", "/r/a.go"}},
+		{"code --wait", 7, []string{"code", "--wait", "--goto", "/r/a.go:7"}},
+		{"/usr/bin/hx", 2, []string{"/usr/bin/hx", "/r/a.go:2"}},
+		{"emacs -nw", 0, []string{"emacs", "-nw", "/r/a.go"}},
+	}
+	for _, tc := range cases {
+		if got := editorCommand(tc.editor, "/r/a.go", tc.line); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("editorCommand(%q, %d) = %q, want %q", tc.editor, tc.line, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// eventJSON is one line of --events=json output.
+type eventJSON struct {
+	Type      string    `json:"type"` // added, changed or removed
+	Path      string    `json:"path"`
+	OldStatus string    `json:"old_status"`
+	Status    string    `json:"status"`
+	At        time.Time `json:"at"`
+	Added     int       `json:"added"`
+	Deleted   int       `json:"deleted"`
+	Binary    bool      `json:"binary,omitempty"`
+}
+
+func newEventJSON(e changeEvent) eventJSON {
+	return eventJSON{
+		Type:      e.kind(),
+		Path:      e.path,
+		OldStatus: e.oldStatus,
+		Status:    e.status,
+		At:        e.at,
+		Added:     e.stat.added,
+		Deleted:   e.stat.deleted,
+		Binary:    e.stat.binary,
+	}
+}
+
+// writeEvents writes events as NDJSON, one object per line.
+func writeEvents(w io.Writer, events []changeEvent) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(newEventJSON(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runEvents watches the repo without the TUI and writes every change event
+// to w until it fails. The files changed at startup are the baseline, not
+// events.
+func runEvents(w io.Writer, base string) error {
+	files, err := getChangedFiles(base)
+	if err != nil {
+		return err
+	}
+	snap := newSnapshot(files)
+
+	interval := cfg.Refresh.Poll.Duration
+	var changes <-chan struct{}
+	if cfg.Refresh.Watch && !forcePoll {
+		if gitDir, err := gitCmd("rev-parse", "--absolute-git-dir"); err == nil {
+			if fw, err := startWatcher(workDir, strings.TrimSpace(gitDir)); err == nil {
+				defer fw.stop()
+				changes = fw.changes
+				interval = cfg.Refresh.SafetyPoll.Duration
+			}
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case _, ok := <-changes:
+			if !ok {
+				return fmt.Errorf("the filesystem watcher stopped")
+			}
+		case <-ticker.C:
+		}
+		files, err := getChangedFiles(base)
+		if err != nil {
+			// git can fail mid-operation (e.g. a held index.lock); try again next time
+			continue
+		}
+		if err := writeEvents(w, snap.diff(files)); err != nil {
+			return err
+		}
+		snap = newSnapshot(files)
+	}
+}
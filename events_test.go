@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNumstat(t *testing.T) {
+	out := "3\t1\tmain.go\x00-\t-\tlogo.png\x000\t2\t\x00old.go\x00new.go\x00"
+	got := parseNumstat(out)
+	want := map[string]diffStat{
+		"main.go":  {added: 3, deleted: 1},
+		"logo.png": {binary: true},
+		"new.go":   {deleted: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for path, st := range want {
+		if got[path] != st {
+			t.Errorf("%s: got %+v, want %+v", path, got[path], st)
+		}
+	}
+
+	if st := countLines("a\nb"); st.added != 2 {
+		t.Errorf("countLines without a final newline: got %d, want 2", st.added)
+	}
+}
+
+func TestSnapshotDiffEvents(t *testing.T) {
+	snap := newSnapshot([]fileEntry{
+		{status: "M", path: "b.go", stat: diffStat{added: 1}},
+		{status: "??", path: "c.go", stat: diffStat{added: 4}},
+		{status: "M", path: "d.go", stat: diffStat{added: 2}},
+	})
+	changes := snap.diff([]fileEntry{
+		{status: "A", path: "a.go", stat: diffStat{added: 7}},
+		{status: "M", path: "b.go", stat: diffStat{added: 3, deleted: 1}}, // edited again
+		{status: "A", path: "c.go", stat: diffStat{added: 4}},
+		// d.go committed
+	})
+
+	want := []struct{ path, kind, old, status string }{
+		{"a.go", "added", "", "A"},
+		{"b.go", "changed", "M", "M"},
+		{"c.go", "changed", "??", "A"},
+		{"d.go", "removed", "M", ""},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d events, want %d", len(changes), len(want))
+	}
+	for i, w := range want {
+		e := changes[i]
+		if e.path != w.path || e.kind() != w.kind || e.oldStatus != w.old || e.status != w.status {
+			t.Errorf("event %d: got %s %s %q→%q, want %s %s %q→%q",
+				i, e.path, e.kind(), e.oldStatus, e.status, w.path, w.kind, w.old, w.status)
+		}
+	}
+
+	var buf bytes.Buffer
+	changes[1].at = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	if err := writeEvents(&buf, changes[1:2]); err != nil {
+		t.Fatal(err)
+	}
+	line := `{"type":"changed","path":"b.go","old_status":"M","status":"M","at":"2026-01-02T15:04:05Z","added":3,"deleted":1}`
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != line {
+		t.Errorf("got %s\nwant %s", got, line)
+	}
+}
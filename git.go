@@ -1,17 +1,28 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 	"unicode/utf8"
 )
 
 type fileEntry struct {
-	status string
-	path   string
+	status   string // collapsed status used for the badge ("M", "A", "??", ...)
+	index    byte   // porcelain X column: state in the index (staged)
+	worktree byte   // porcelain Y column: state in the worktree (unstaged)
+	path     string
+	stat     diffStat // lines changed against HEAD, or the base
+	blob     string   // snapshot of the content at the last refresh, if captured
 }
 
 func (f fileEntry) Title() string       { return f.path }
@@ -22,6 +33,16 @@ func (f fileEntry) StatusLabel() string {
 	return statusLabel(f.status)
 }
 
+// hasStaged reports whether the index differs from HEAD for this file.
+func (f fileEntry) hasStaged() bool {
+	return f.index != 0 && f.index != ' ' && f.index != '?'
+}
+
+// hasUnstaged reports whether the worktree differs from the index for this file.
+func (f fileEntry) hasUnstaged() bool {
+	return f.worktree != 0 && f.worktree != ' ' && f.worktree != '?'
+}
+
 func getCurrentBranch() string {
 	out, err := gitCmd("rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
@@ -33,6 +54,9 @@ func getCurrentBranch() string {
 func gitCmd(args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = workDir
+	// Read-only commands must not refresh .git/index, or the watcher would
+	// see our own `git status` as a change and refresh forever.
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
 	out, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -40,26 +64,216 @@ func gitCmd(args ...string) (string, error) {
 	return string(out), nil
 }
 
-func getChangedFiles() ([]fileEntry, error) {
+// getChangedFiles lists uncommitted changes, or with a base every file that
+// differs between base and the worktree (including untracked files).
+func getChangedFiles(base string) ([]fileEntry, error) {
 	// Use -uall to expand untracked directories into individual files
 	out, err := gitCmd("status", "--porcelain", "-uall")
 	if err != nil {
 		return nil, err
 	}
+	if base == "" {
+		files := parsePorcelain(out)
+		addNumstat(files, "HEAD")
+		return files, nil
+	}
+
+	rev, err := resolveBase(base)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := gitCmd("diff", "--name-status", "-z", "-M", rev)
+	if err != nil {
+		return nil, err
+	}
+	files := parseNameStatus(diff)
+	for _, f := range parsePorcelain(out) {
+		if f.status == "??" {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	addNumstat(files, rev)
+	return files, nil
+}
+
+// diffStat counts the lines a change adds and deletes; binary files have no
+// line counts.
+type diffStat struct {
+	added, deleted int
+	binary         bool
+}
+
+// countMaxSize is the largest untracked file whose lines are counted;
+// bigger ones are usually build output and are left uncounted.
+const countMaxSize = 4 << 20
+
+// untrackedCounts caches untracked files' line counts by size and mtime, so a
+// refresh only reads the files that changed since the last one.
+var untrackedCounts = struct {
+	sync.Mutex
+	byPath map[string]countedFile
+}{byPath: map[string]countedFile{}}
+
+type countedFile struct {
+	size  int64
+	mtime time.Time
+	stat  diffStat
+}
+
+// addNumstat fills in each file's diffstat against rev. Untracked files count
+// as wholly added. Stats are best effort: without them the list still works.
+func addNumstat(files []fileEntry, rev string) {
+	tracked := slices.ContainsFunc(files, func(f fileEntry) bool { return f.status != "??" })
+	var stats map[string]diffStat
+	if tracked {
+		out, err := gitCmd("diff", "--numstat", "-z", "-M", rev)
+		if err != nil {
+			// No commits yet: staged and unstaged changes are all there is
+			out, _ = gitCmd("diff", "--numstat", "-z", "--cached")
+			worktree, _ := gitCmd("diff", "--numstat", "-z")
+			out += worktree
+		}
+		stats = parseNumstat(out)
+	}
+
+	untrackedCounts.Lock()
+	defer untrackedCounts.Unlock()
+	cached := untrackedCounts.byPath
+	untrackedCounts.byPath = map[string]countedFile{}
+	for i, f := range files {
+		if f.status != "??" {
+			files[i].stat = stats[f.path]
+			continue
+		}
+		info, err := os.Stat(filepath.Join(workDir, f.path))
+		if err != nil || !info.Mode().IsRegular() || info.Size() > countMaxSize {
+			continue
+		}
+		c, ok := cached[f.path]
+		if !ok || c.size != info.Size() || !c.mtime.Equal(info.ModTime()) {
+			content, err := readFile(f.path)
+			if err != nil {
+				continue
+			}
+			c = countedFile{size: info.Size(), mtime: info.ModTime(), stat: countLines(content)}
+		}
+		untrackedCounts.byPath[f.path] = c
+		files[i].stat = c.stat
+	}
+}
+
+// parseNumstat reads `git diff --numstat -z` output into stats by path.
+// Renames list the counts, then the old and new paths as separate fields.
+func parseNumstat(out string) map[string]diffStat {
+	stats := map[string]diffStat{}
+	fields := strings.Split(out, "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		path := parts[2]
+		if path == "" && i+2 < len(fields) {
+			path = fields[i+2]
+			i += 2
+		}
+		var st diffStat
+		if parts[0] == "-" {
+			st.binary = true
+		} else {
+			st.added, _ = strconv.Atoi(parts[0])
+			st.deleted, _ = strconv.Atoi(parts[1])
+		}
+		prev := stats[path]
+		st.added += prev.added
+		st.deleted += prev.deleted
+		st.binary = st.binary || prev.binary
+		stats[path] = st
+	}
+	return stats
+}
+
+// countLines is the diffstat of adding content as a new file.
+func countLines(content string) diffStat {
+	if isBinary(content) {
+		return diffStat{binary: true}
+	}
+	n := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		n++
+	}
+	return diffStat{added: n}
+}
+
+// resolveBase turns a base spec into a commit: a ref or revision, or
+// "<ref>..." for the merge-base of ref and HEAD.
+func resolveBase(base string) (string, error) {
+	if ref, ok := strings.CutSuffix(base, "..."); ok {
+		out, err := gitCmd("merge-base", ref, "HEAD")
+		if err != nil {
+			return "", fmt.Errorf("no merge-base between %s and HEAD", ref)
+		}
+		return strings.TrimSpace(out), nil
+	}
+	out, err := gitCmd("rev-parse", "--verify", "--quiet", base+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown base %q", base)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// getRefs lists local branches, remote branches and tags for the base picker.
+func getRefs() ([]string, error) {
+	out, err := gitCmd("for-each-ref", "--format=%(refname:short)", "refs/heads", "refs/remotes", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	return parseRefs(out), nil
+}
+
+// parseRefs reads `git for-each-ref --format=%(refname:short)` output.
+func parseRefs(out string) []string {
+	var refs []string
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		// Skip symbolic refs like origin/HEAD, and empty output
+		if line != "" && !strings.HasSuffix(line, "/HEAD") {
+			refs = append(refs, line)
+		}
+	}
+	return refs
+}
+
+// parsePorcelain parses `git status --porcelain` output, keeping the index (X)
+// and worktree (Y) columns separately alongside a collapsed badge status.
+func parsePorcelain(out string) []fileEntry {
 	var files []fileEntry
 	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
 		if len(line) < 4 {
 			continue
 		}
-		status := strings.TrimSpace(line[:2])
+		x, y := line[0], line[1]
 		path := strings.TrimSpace(line[3:])
 		// Handle renames: "R  old -> new"
 		if idx := strings.Index(path, " -> "); idx != -1 {
 			path = path[idx+4:]
 		}
-		files = append(files, fileEntry{status: status, path: path})
+		files = append(files, fileEntry{status: collapseStatus(x, y), index: x, worktree: y, path: path})
 	}
-	return files, nil
+	return files
+}
+
+// collapseStatus reduces the XY columns to the single status shown in the
+// badge: untracked stays "??", otherwise the staged state wins over the
+// worktree state ("MM" → "M", "AM" → "A", " D" → "D").
+func collapseStatus(x, y byte) string {
+	if x == '?' && y == '?' {
+		return "??"
+	}
+	if x != ' ' {
+		return string(x)
+	}
+	return string(y)
 }
 
 func getAllFiles() ([]fileEntry, error) {
@@ -77,32 +291,249 @@ func getAllFiles() ([]fileEntry, error) {
 	return files, nil
 }
 
-func getDiff(path string) (string, error) {
-	// Try staged diff first, then unstaged
-	out, err := gitCmd("diff", "--cached", "--", path)
+// getDiff returns the unstaged (worktree vs index) diff for path, or the
+// staged (index vs HEAD) diff when staged is set. With a base it returns the
+// worktree vs base diff instead.
+func getDiff(path string, staged bool, base string) (string, error) {
+	if base != "" {
+		rev, err := resolveBase(base)
+		if err != nil {
+			return "", err
+		}
+		return gitCmd("diff", rev, "--", path)
+	}
+	if staged {
+		return gitCmd("diff", "--cached", "--", path)
+	}
+	return gitCmd("diff", "--", path)
+}
+
+// getHeadDiff returns every uncommitted change to path, staged or not, so
+// line numbers refer to the file as it is on disk. Falls back to the
+// worktree diff in a repo without commits.
+func getHeadDiff(path string) (string, error) {
+	out, err := gitCmd("diff", "HEAD", "--", path)
+	if err != nil {
+		return getDiff(path, false, "")
+	}
+	return out, nil
+}
+
+// commitEntry is one commit in the history view.
+type commitEntry struct {
+	hash    string
+	short   string
+	author  string
+	when    time.Time
+	subject string
+}
+
+func (c commitEntry) FilterValue() string { return c.short + " " + c.author + " " + c.subject }
+
+// getLog returns the most recent commits on HEAD, newest first.
+func getLog(limit int) ([]commitEntry, error) {
+	out, err := gitCmd("log", fmt.Sprintf("--max-count=%d", limit), "--format=%H%x00%h%x00%an%x00%at%x00%s")
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(out), nil
+}
+
+// parseLog parses getLog's NUL-separated format, one commit per line.
+func parseLog(out string) []commitEntry {
+	var commits []commitEntry
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		fields := strings.SplitN(line, "\x00", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		secs, _ := strconv.ParseInt(fields[3], 10, 64)
+		commits = append(commits, commitEntry{
+			hash:    fields[0],
+			short:   fields[1],
+			author:  fields[2],
+			when:    time.Unix(secs, 0),
+			subject: fields[4],
+		})
+	}
+	return commits
+}
+
+// commitBase returns what rev is compared against: its first parent, or the
+// empty tree for a root commit.
+func commitBase(rev string) (string, error) {
+	if out, err := gitCmd("rev-parse", "--verify", "--quiet", rev+"^"); err == nil {
+		return strings.TrimSpace(out), nil
+	}
+	out, err := gitCmd("hash-object", "-t", "tree", os.DevNull)
 	if err != nil {
 		return "", err
 	}
-	if strings.TrimSpace(out) != "" {
-		return out, nil
+	return strings.TrimSpace(out), nil
+}
+
+// getCommitFiles lists the files rev changed relative to its first parent.
+func getCommitFiles(rev string) ([]fileEntry, error) {
+	base, err := commitBase(rev)
+	if err != nil {
+		return nil, err
+	}
+	out, err := gitCmd("diff", "--name-status", "-z", "-M", base, rev)
+	if err != nil {
+		return nil, err
+	}
+	return parseNameStatus(out), nil
+}
+
+// parseNameStatus parses `git diff --name-status -z` output. Renames and
+// copies carry two paths; the entry keeps the new one.
+func parseNameStatus(out string) []fileEntry {
+	var files []fileEntry
+	fields := strings.Split(strings.TrimRight(out, "\x00"), "\x00")
+	for i := 0; i < len(fields); i++ {
+		code := fields[i]
+		if code == "" {
+			continue
+		}
+		status := code[:1]
+		if status == "R" || status == "C" {
+			i++ // old path
+		}
+		i++
+		if i >= len(fields) {
+			break
+		}
+		switch status {
+		case "C":
+			status = "A"
+		case "T":
+			status = "M"
+		}
+		files = append(files, fileEntry{status: status, path: fields[i]})
 	}
-	out, err = gitCmd("diff", "--", path)
+	return files
+}
+
+// getCommitDiff returns the diff rev introduced to path.
+func getCommitDiff(rev, path string) (string, error) {
+	base, err := commitBase(rev)
 	if err != nil {
 		return "", err
 	}
-	return out, nil
+	return gitCmd("diff", base, rev, "--", path)
+}
+
+// getFileAtRev returns path's content as of rev.
+func getFileAtRev(rev, path string) (string, error) {
+	return gitCmd("show", rev+":"+path)
+}
+
+// grepMatch is one line found by git grep.
+type grepMatch struct {
+	path string
+	line int // 1-based
+	text string
+}
+
+// gitGrep searches tracked files, and untracked ones if asked, for the
+// extended regexp pattern. As in the viewer's search, a pattern without
+// capitals ignores case.
+func gitGrep(pattern string, untracked bool) ([]grepMatch, error) {
+	args := []string{"grep", "-n", "-z", "-I", "-E", "--full-name", "--no-color"}
+	if strings.ToLower(pattern) == pattern {
+		args = append(args, "-i")
+	}
+	if untracked {
+		args = append(args, "--untracked")
+	}
+	args = append(args, "-e", pattern)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) && exit.ExitCode() == 1 && stderr.Len() == 0 {
+			return nil, nil // no matches
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s", strings.TrimPrefix(msg, "fatal: "))
+		}
+		return nil, err
+	}
+	return parseGrep(string(out)), nil
+}
+
+// parseGrep reads `git grep -n -z` output: path, line and text separated by NULs.
+func parseGrep(out string) []grepMatch {
+	var matches []grepMatch
+	for _, rec := range strings.Split(out, "\n") {
+		parts := strings.SplitN(rec, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		matches = append(matches, grepMatch{path: parts[0], line: n, text: strings.TrimRight(parts[2], "\r")})
+	}
+	return matches
+}
+
+// gitApply feeds patch to `git apply` on stdin with the given flags
+// (e.g. --cached to stage, --cached --reverse to unstage).
+func gitApply(patch string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"apply"}, args...)...)
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(patch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// replaceFileLines replaces lines from..to (0-based, inclusive) with
+// newLines, which may be more or fewer lines, keeping the file's permissions
+// and line endings. It returns the lines it replaced.
+func replaceFileLines(path string, from, to int, newLines []string) ([]string, error) {
+	locate := func(_ []byte, _ []string) (int, int, error) { return from, to, nil }
+	old, _, err := spliceFile(path, locate, newLines)
+	return old, err
+}
+
+// swapFileLines replaces the lines expect at from with newLines, failing if
+// the file no longer has expect there.
+func swapFileLines(path string, from int, expect, newLines []string) error {
+	locate := func(_ []byte, lines []string) (int, int, error) {
+		to := from + len(expect) - 1
+		if to >= len(lines) || !slices.Equal(lines[from:to+1], expect) {
+			return 0, 0, fmt.Errorf("%s changed since the edit", path)
+		}
+		return from, to, nil
+	}
+	_, _, err := spliceFile(path, locate, newLines)
+	return err
 }
 
-// writeFileLine replaces a single line in a file, preserving permissions and line endings.
-func writeFileLine(path string, lineNum int, newContent string) error {
+// spliceFile replaces the lines that locate picks from the file's current
+// content with newLines, returning the old lines and where they started.
+// locate may return to == from-1 to insert without replacing.
+func spliceFile(path string, locate func(data []byte, lines []string) (from, to int, err error), newLines []string) ([]string, int, error) {
 	full := filepath.Join(workDir, path)
 	info, err := os.Stat(full)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 	data, err := os.ReadFile(full)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 	text := string(data)
 
@@ -111,16 +542,84 @@ func writeFileLine(path string, lineNum int, newContent string) error {
 	if strings.Contains(text, "\r\n") {
 		eol = "\r\n"
 	}
-
-	// Split, replace, rejoin
 	lines := strings.Split(text, eol)
-	if lineNum < 0 || lineNum >= len(lines) {
-		return fmt.Errorf("line %d out of range (file has %d lines)", lineNum, len(lines))
+	from, to, err := locate(data, lines)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from < 0 || to < from-1 || to >= len(lines) {
+		return nil, 0, fmt.Errorf("lines %d-%d out of range (file has %d lines)", from+1, to+1, len(lines))
+	}
+	old := slices.Clone(lines[from : to+1])
+	out := make([]string, 0, len(lines)-len(old)+len(newLines))
+	out = append(out, lines[:from]...)
+	out = append(out, newLines...)
+	out = append(out, lines[to+1:]...)
+
+	if err := writeFileAtomic(full, []byte(strings.Join(out, eol)), info.Mode()); err != nil {
+		return nil, 0, err
+	}
+	return old, from, nil
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it
+// over path, so readers (and a crash) never see a half-written file. A
+// symlinked path is written through to its target, like os.WriteFile.
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	return replaceFileAtomic(path, data, mode)
+}
+
+// replaceFileAtomic is writeFileAtomic for path itself: a symlink there is
+// replaced by the new file.
+func replaceFileAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".owl-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
 	}
-	lines[lineNum] = newContent
-	result := strings.Join(lines, eol)
+	return os.Rename(tmp.Name(), path)
+}
 
-	return os.WriteFile(full, []byte(result), info.Mode())
+// gitCommit commits the index with message and returns "<short hash> <subject>",
+// or just the subject if the new commit can't be read back.
+func gitCommit(message string, amend, signoff bool) (string, error) {
+	args := []string{"commit", "--file=-"}
+	if amend {
+		args = append(args, "--amend")
+	}
+	if signoff {
+		args = append(args, "--signoff")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	out, err := gitCmd("log", "-1", "--format=%h %s")
+	if err != nil {
+		// The commit went through; describe it without the hash
+		subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+		return subject, nil
+	}
+	return strings.TrimSpace(out), nil
 }
 
 func readFile(path string) (string, error) {
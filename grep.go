@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// grepLimit caps how many matches the grep view lists.
+const grepLimit = 1000
+
+type grepDoneMsg struct {
+	query     string
+	untracked bool
+	matches   []grepMatch
+	err       error
+}
+
+func runGrep(query string, untracked bool) tea.Cmd {
+	return func() tea.Msg {
+		matches, err := gitGrep(query, untracked)
+		return grepDoneMsg{query: query, untracked: untracked, matches: matches, err: err}
+	}
+}
+
+// grepEntry is a row in the grep view: a file heading its matches, or one
+// matching line of it.
+type grepEntry struct {
+	path  string
+	line  int // 1-based; 0 for the file heading
+	text  string
+	count int // matches in the file, on headings
+}
+
+func (g grepEntry) FilterValue() string { return g.path + " " + g.text }
+
+// grepItems groups matches, which git lists file by file, under a heading
+// for each file.
+func grepItems(matches []grepMatch) []list.Item {
+	var items []list.Item
+	head := -1
+	for _, mt := range matches {
+		if head < 0 || items[head].(grepEntry).path != mt.path {
+			head = len(items)
+			items = append(items, grepEntry{path: mt.path})
+		}
+		h := items[head].(grepEntry)
+		h.count++
+		items[head] = h
+		items = append(items, grepEntry{path: mt.path, line: mt.line, text: mt.text})
+	}
+	return items
+}
+
+// grepDelegate renders file headings like the file list and matching lines
+// indented beneath them, with the matches highlighted.
+type grepDelegate struct {
+	re *regexp.Regexp
+}
+
+func (d grepDelegate) Height() int                             { return 1 }
+func (d grepDelegate) Spacing() int                            { return 0 }
+func (d grepDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d grepDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	g, ok := item.(grepEntry)
+	if !ok {
+		return
+	}
+	isSelected := index == m.Index()
+	maxWidth := m.Width()
+
+	prefix := "  "
+	if isSelected {
+		prefix = cursorStyle.Render("> ")
+	}
+	dirStyle, fileStyle, dimStyle, numStyle, textStyle := pathDirStyle, pathFileStyle, headerDimStyle, lineNumStyle, lipgloss.NewStyle().Foreground(colorFg)
+	if isSelected {
+		dirStyle = dirStyle.Background(colorHighlight)
+		fileStyle = fileStyle.Background(colorHighlight)
+		dimStyle = dimStyle.Background(colorHighlight)
+		numStyle = numStyle.Background(colorHighlight)
+		textStyle = textStyle.Background(colorHighlight)
+	}
+
+	var row string
+	if g.line == 0 {
+		dir, file := splitPath(g.path)
+		if dir != "" {
+			row = prefix + dirStyle.Render(dir+"/") + fileStyle.Render(file)
+		} else {
+			row = prefix + fileStyle.Render(file)
+		}
+		noun := "matches"
+		if g.count == 1 {
+			noun = "match"
+		}
+		row += dimStyle.Render(fmt.Sprintf("  %d %s", g.count, noun))
+	} else {
+		row = prefix + numStyle.Width(7).Render(strconv.Itoa(g.line)) + dimStyle.Render("  ")
+		text := strings.TrimSpace(strings.ReplaceAll(g.text, "\t", "    "))
+		var spans []wordSpan
+		if d.re != nil {
+			spans = matchSpans(text, d.re)
+		}
+		at := 0
+		for _, sp := range spans {
+			row += textStyle.Render(text[at:sp.from]) + searchMatchStyle.Render(text[sp.from:sp.to])
+			at = sp.to
+		}
+		row += textStyle.Render(text[at:])
+	}
+	row = ansi.Truncate(row, maxWidth, "…")
+
+	if isSelected {
+		if rowLen := lipgloss.Width(row); rowLen < maxWidth {
+			row += selectedRowStyle.Render(strings.Repeat(" ", maxWidth-rowLen))
+		}
+	}
+	fmt.Fprint(w, row)
+}
+
+// openGrep shows the grep view with its query prompt, seeded with the last
+// query or the viewer's search.
+func (m model) openGrep() (tea.Model, tea.Cmd) {
+	if m.logCommit != nil {
+		m.setFlash("grep searches the working tree; leave the commit first", true)
+		return m, nil
+	}
+	if m.currentView != grepView {
+		m.grepPrevView = m.currentView
+	}
+	query := m.grepQuery
+	if query == "" {
+		query = m.searchQuery
+	}
+	innerW, _ := m.innerSize()
+	ti := textinput.New()
+	ti.Prompt = "grep "
+	ti.PromptStyle = filterPromptStyle
+	ti.CharLimit = 0
+	ti.Width = innerW - 8
+	ti.SetValue(query)
+	ti.CursorEnd()
+	m.grepInput = ti
+	m.grepping = true
+	m.currentView = grepView
+	return m, m.grepInput.Focus()
+}
+
+// updateGrep handles keys in the grep view and its query prompt.
+func (m model) updateGrep(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.grepping {
+		switch msg.Type {
+		case tea.KeyEnter:
+			query := m.grepInput.Value()
+			if query == "" {
+				return m, nil
+			}
+			m.grepping = false
+			m.grepQuery = query
+			m.grepRe, _ = compileSearch(query)
+			m.grepLoading = true
+			return m, runGrep(query, m.grepUntracked)
+
+		case tea.KeyEsc:
+			m.grepping = false
+			if m.grepQuery == "" {
+				m.currentView = m.grepPrevView
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.grepInput, cmd = m.grepInput.Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Back):
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.grepList.FilterState() == list.FilterApplied {
+			m.grepList.ResetFilter()
+			return m, nil
+		}
+		m.currentView = m.grepPrevView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Grep):
+		return m.openGrep()
+
+	case key.Matches(msg, m.keys.GrepUntracked):
+		m.grepUntracked = !m.grepUntracked
+		if m.grepQuery == "" {
+			return m, nil
+		}
+		m.grepLoading = true
+		return m, runGrep(m.grepQuery, m.grepUntracked)
+
+	case key.Matches(msg, m.keys.Refresh):
+		if m.grepQuery == "" {
+			return m, nil
+		}
+		m.grepLoading = true
+		return m, runGrep(m.grepQuery, m.grepUntracked)
+
+	case key.Matches(msg, m.keys.Open):
+		return m.openGrepMatch()
+	}
+
+	var cmd tea.Cmd
+	m.grepList, cmd = m.grepList.Update(msg)
+	return m, cmd
+}
+
+// grepLoaded shows the results of a grep, unless the query has moved on.
+func (m model) grepLoaded(msg grepDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.query != m.grepQuery || msg.untracked != m.grepUntracked {
+		return m, nil
+	}
+	m.grepLoading = false
+	if msg.err != nil {
+		m.setFlash("git grep: "+msg.err.Error(), true)
+		return m, nil
+	}
+	matches := msg.matches
+	m.grepTotal = len(matches)
+	if len(matches) > grepLimit {
+		matches = matches[:grepLimit]
+		m.setFlash(fmt.Sprintf("showing the first %d of %d matches", grepLimit, m.grepTotal), false)
+	}
+	items := grepItems(matches)
+	m.grepFiles = 0
+	for _, it := range items {
+		if it.(grepEntry).line == 0 {
+			m.grepFiles++
+		}
+	}
+	m.grepList.ResetFilter()
+	m.grepList.SetItems(items)
+	m.grepList.Select(min(1, len(items)-1)) // the first match, past its heading
+	return m, nil
+}
+
+// openGrepMatch opens the selected match in the viewer with the cursor on
+// its line and the query highlighted; a heading opens at its first match.
+func (m model) openGrepMatch() (tea.Model, tea.Cmd) {
+	g, ok := m.grepList.SelectedItem().(grepEntry)
+	if !ok {
+		return m, nil
+	}
+	if g.line == 0 {
+		items := m.grepList.VisibleItems()
+		if i := m.grepList.Index() + 1; i < len(items) {
+			if next := items[i].(grepEntry); next.path == g.path {
+				g = next
+			}
+		}
+	}
+
+	innerW, innerH := m.innerSize()
+	m.returnViewerTo(grepView)
+	m.currentFile = g.path
+	m.hScroll = 0
+	m.diffMode = false
+	m.diffStaged = false
+	m.mdPreview = false
+	m.visual = false
+	m.search, m.searchQuery = m.grepRe, m.grepQuery
+	m.cursorLine = max(g.line-1, 0)
+	m.quickFixPending = true
+	m.quickFixYOffset = max(m.cursorLine-(innerH-2)/2, 0)
+	m.loadSeq++
+	m.viewport = viewport.New(innerW-1, innerH-2)
+	m.viewport.SetContent("Loading...")
+	return m, m.loadContent(g.path, "")
+}
+
+// renderGrep draws the query line above the results.
+func (m model) renderGrep() string {
+	var top string
+	switch {
+	case m.grepping:
+		top = " " + m.grepInput.View()
+	case m.grepLoading:
+		top = " " + filterPromptStyle.Render("grep ") + m.grepQuery + headerDimStyle.Render("  searching…")
+	default:
+		summary := "  no matches"
+		if m.grepTotal > 0 {
+			summary = fmt.Sprintf("  %d matches in %d files", m.grepTotal, m.grepFiles)
+		}
+		if m.grepUntracked {
+			summary += ", untracked included"
+		}
+		top = " " + filterPromptStyle.Render("grep ") + m.grepQuery + headerDimStyle.Render(summary)
+	}
+	innerW, _ := m.innerSize()
+	m.grepList.SetDelegate(grepDelegate{re: m.grepRe})
+	return ansi.Truncate(top, innerW, "…") + "\n" + m.renderList(m.grepList)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGrep(t *testing.T) {
+	out := "a.go\x003\x00func oldName() {\n" +
+		"a.go\x0010\x00\toldName()\r\n" +
+		"dir/b.go\x007\x00x := oldName // a\x00b\n"
+	want := []grepMatch{
+		{path: "a.go", line: 3, text: "func oldName() {"},
+		{path: "a.go", line: 10, text: "\toldName()"},
+		{path: "dir/b.go", line: 7, text: "x := oldName // a\x00b"},
+	}
+	if got := parseGrep(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGrep = %+v\nwant %+v", got, want)
+	}
+	if got := parseGrep(""); got != nil {
+		t.Errorf("empty output parsed as %v", got)
+	}
+}
+
+func TestGrepItemsGroupsByFile(t *testing.T) {
+	items := grepItems([]grepMatch{
+		{path: "a.go", line: 3, text: "x"},
+		{path: "a.go", line: 10, text: "y"},
+		{path: "b.go", line: 1, text: "z"},
+	})
+	var got []grepEntry
+	for _, it := range items {
+		got = append(got, it.(grepEntry))
+	}
+	want := []grepEntry{
+		{path: "a.go", count: 2},
+		{path: "a.go", line: 3, text: "x"},
+		{path: "a.go", line: 10, text: "y"},
+		{path: "b.go", count: 1},
+		{path: "b.go", line: 1, text: "z"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("grepItems = %+v\nwant %+v", got, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // renderHeader produces a 2-line animated header, full terminal width.
@@ -22,6 +23,10 @@ func (m model) renderHeader() string {
 
 	// Dirty indicator + file count
 	fileCount := len(m.list.Items())
+	if !m.treeMode {
+		// Not the section headers
+		fileCount = len(listFiles(m.list.Items()))
+	}
 	var dirty string
 	if fileCount > 0 && !m.allFiles {
 		dirty = dirtyIndicatorStyle.Render("●")
@@ -29,6 +34,10 @@ func (m model) renderHeader() string {
 		dirty = cleanIndicatorStyle.Render("✓")
 	}
 	count := fileCountStyle.Render(fmt.Sprintf("%d files", fileCount))
+	if m.showStats() && fileCount > 0 {
+		total, _ := listStats(m.list.Items())
+		count += " " + renderStat(total, nil)
+	}
 
 	// ── Line 1: logo ... badges + owl top ──
 	line1Left := indent + logo
@@ -38,6 +47,12 @@ func (m model) renderHeader() string {
 	if m.diffMode {
 		line1RightParts = append(line1RightParts, diffBadgeStyle.Render("DIFF"))
 	}
+	if m.base != "" {
+		line1RightParts = append(line1RightParts, baseBadgeStyle.Render("vs "+m.base))
+	}
+	if m.currentView == logView || m.logCommit != nil {
+		line1RightParts = append(line1RightParts, historyBadgeStyle.Render("LOG"))
+	}
 	if m.treeMode {
 		line1RightParts = append(line1RightParts, treeBadgeStyle.Render("TREE"))
 	} else if m.allFiles {
@@ -61,6 +76,9 @@ func (m model) renderHeader() string {
 		treePath := breadcrumbDirStyle.Render("  " + m.treeCwd.path + "/")
 		line2Left += treePath
 	}
+	if m.logCommit != nil {
+		line2Left += "  " + logHashStyle.Render(m.logCommit.short) + " " + breadcrumbDirStyle.Render(m.logCommit.subject)
+	}
 
 	line2Right := owlStyle.Render(m.owl.owlBottom()) + rightPad
 
@@ -77,8 +95,7 @@ func (m model) renderHeader() string {
 
 // renderWithHelpOverlay renders the help overlay centered over the panel.
 func (m model) renderWithHelpOverlay(header, panel, cmdbar string) string {
-	helpContent := m.renderHelpContent()
-	overlay := helpOverlayStyle.Render(helpContent)
+	overlay := helpOverlayStyle.Render(m.visibleHelp())
 
 	panelHeight := lipgloss.Height(panel)
 
@@ -92,6 +109,39 @@ func (m model) renderWithHelpOverlay(header, panel, cmdbar string) string {
 	return header + "\n" + placed + "\n" + cmdbar
 }
 
+// helpRows is how many lines of help fit inside the overlay's frame.
+func (m model) helpRows() int {
+	_, innerH := m.innerSize()
+	return max(innerH+2-helpOverlayStyle.GetVerticalFrameSize(), 3)
+}
+
+// helpMaxScroll is the furthest the help scrolls, leaving a line for the
+// scroll hint.
+func (m model) helpMaxScroll() int {
+	return max(strings.Count(m.renderHelpContent(), "\n")+1-(m.helpRows()-1), 0)
+}
+
+// visibleHelp is the part of the help that fits the panel, with a hint
+// for scrolling to the rest.
+func (m model) visibleHelp() string {
+	lines := strings.Split(m.renderHelpContent(), "\n")
+	maxW := max(m.width-helpOverlayStyle.GetHorizontalFrameSize(), 10)
+	w := 0
+	for i, l := range lines {
+		lines[i] = ansi.Truncate(l, maxW, "…")
+		w = max(w, lipgloss.Width(lines[i]))
+	}
+	rows := m.helpRows()
+	if len(lines) > rows {
+		off := min(m.helpScroll, m.helpMaxScroll())
+		end := off + rows - 1
+		hint := fmt.Sprintf("%s to scroll (%d-%d of %d)", keyHelp(m.keys.Down, m.keys.Up), off+1, end, len(lines))
+		lines = append(lines[off:end], headerDimStyle.Render(ansi.Truncate(hint, maxW, "…")))
+	}
+	// As wide as the whole help, so scrolling doesn't resize the box
+	return lipgloss.NewStyle().Width(w).Render(strings.Join(lines, "\n"))
+}
+
 // renderHelpContent produces the help text.
 func (m model) renderHelpContent() string {
 	title := headerAccentStyle.Render("Keybindings")
@@ -109,28 +159,60 @@ func (m model) renderHelpContent() string {
 		return header + "\n" + strings.Join(lines, "\n")
 	}
 
+	k := m.keys
 	nav := renderSection("Navigation", []binding{
-		{"enter", "Open file"},
-		{"esc", "Back"},
-		{"j/k/↑/↓", "Move cursor"},
-		{"Shift-↑/↓", "Half-page jump"},
-		{"g/G", "Top / bottom"},
-		{"h/l/←/→", "Pan left / right"},
+		{keyHelp(k.Open), "Open file"},
+		{keyHelp(k.Back), "Back"},
+		{keyHelp(k.Down, k.Up), "Move cursor"},
+		{keyHelp(k.HalfPageDown, k.HalfPageUp), "Half-page jump"},
+		{keyHelp(k.Top, k.Bottom), "Top / bottom"},
+		{keyHelp(k.Left, k.Right), "Pan left / right"},
 	})
 
 	views := renderSection("Views", []binding{
-		{"d", "Diff mode"},
-		{"p", "Markdown preview"},
-		{"t", "Tree view / all files"},
-		{"/", "Filter"},
-		{"r", "Refresh"},
+		{keyHelp(k.Diff), "Diff mode"},
+		{keyHelp(k.SplitDiff), "Side-by-side diff"},
+		{keyHelp(k.Preview), "Markdown preview"},
+		{keyHelp(k.Blame), "Blame column"},
+		{keyHelp(k.Tree), "Tree view / all files"},
+		{keyHelp(k.Sort), "Sort: path / status / recent / churn / ext"},
+		{keyHelp(k.Group), "Group: none / status / directory"},
+		{keyHelp(k.Log), "Commit history"},
+		{keyHelp(k.Timeline), "Session timeline"},
+		{keyHelp(k.Base), "Compare against a base ref"},
+		{keyHelp(k.Grep), "Grep the repository"},
+		{keyHelp(k.GrepUntracked), "Include untracked (grep)"},
+		{keyHelp(k.Filter), "Filter / search file"},
+		{keyHelp(k.SearchNext, k.SearchPrev), "Next / previous match"},
+		{keyHelp(k.Refresh), "Refresh"},
+	})
+
+	staging := renderSection("Staging (diff)", []binding{
+		{keyHelp(k.ToggleStaged), "Staged / unstaged diff"},
+		{keyHelp(k.StageHunk), "Stage hunk"},
+		{keyHelp(k.UnstageHunk), "Unstage hunk"},
+		{keyHelp(k.DiscardHunk), "Discard hunk"},
+		{keyHelp(k.SelectLines), "Select lines"},
 	})
 
 	actions := renderSection("Actions", []binding{
-		{"e", "Quick fix line"},
-		{"?", "This help"},
-		{"q", "Quit"},
+		{keyHelp(k.Commit), "Commit staged"},
+		{keyHelp(k.QuickFix), "Quick fix line"},
+		{keyHelp(k.BlockEdit), "Edit block of lines"},
+		{keyHelp(k.OpenEditor), "Open in $EDITOR"},
+		{keyHelp(k.Restore), "Restore file to HEAD / index / snapshot"},
+		{keyHelp(k.Undo, k.Redo), "Undo / redo edit"},
+		{keyHelp(k.EditHistory), "Edit history"},
+		{keyHelp(k.Help), "This help"},
+		{keyHelp(k.Quit), "Quit"},
 	})
 
-	return title + "\n\n" + nav + "\n\n" + views + "\n\n" + actions
+	// Two columns when they fit side by side, else one
+	left := nav + "\n\n" + views
+	right := staging + "\n\n" + actions
+	gap := "    "
+	if lipgloss.Width(left)+len(gap)+lipgloss.Width(right)+helpOverlayStyle.GetHorizontalFrameSize() <= m.width {
+		return title + "\n\n" + lipgloss.JoinHorizontal(lipgloss.Top, left, gap, right)
+	}
+	return title + "\n\n" + left + "\n\n" + right
 }
@@ -26,6 +26,14 @@ var (
 	mermaidBlockRe = regexp.MustCompile("(?s)```mermaid\\n(.*?)```")
 )
 
+// setHighlightStyle selects the chroma style by name, keeping the current
+// one if the name is unknown.
+func setHighlightStyle(name string) {
+	if s, ok := styles.Registry[name]; ok {
+		style = s
+	}
+}
+
 func highlight(content, filename string) string {
 	lexer := lexers.Match(filename)
 	if lexer == nil {
@@ -89,13 +97,37 @@ func highlightDiff(diff, filename string) string {
 		hlLines = append(hlLines, "")
 	}
 
+	// Pair each run of removed lines with the added lines right after it and
+	// find the words that changed within each pair
+	emph := make(map[int][]wordSpan)
+	for i := 0; i < len(lineTypes); {
+		if lineTypes[i] != '-' {
+			i++
+			continue
+		}
+		dels := i
+		for i < len(lineTypes) && lineTypes[i] == '-' {
+			i++
+		}
+		adds := i
+		for i < len(lineTypes) && lineTypes[i] == '+' {
+			i++
+		}
+		for k := 0; dels+k < adds && adds+k < i; k++ {
+			oldSpans, newSpans := wordDiff(codeLines[dels+k], codeLines[adds+k])
+			if oldSpans != nil || newSpans != nil {
+				emph[dels+k], emph[adds+k] = oldSpans, newSpans
+			}
+		}
+	}
+
 	// Reassemble with colored prefixes and background tints
 	for i, lt := range lineTypes {
 		switch lt {
 		case '+':
-			hlLines[i] = diffAddedPrefixStyle.Render("+") + injectBg(hlLines[i], diffAddedBgColor)
+			hlLines[i] = diffAddedPrefixStyle.Render("+") + emphasizeSpans(hlLines[i], emph[i], diffAddedBgColor, diffAddedEmphBgColor)
 		case '-':
-			hlLines[i] = diffDeletedPrefixStyle.Render("-") + injectBg(hlLines[i], diffDeletedBgColor)
+			hlLines[i] = diffDeletedPrefixStyle.Render("-") + emphasizeSpans(hlLines[i], emph[i], diffDeletedBgColor, diffDeletedEmphBgColor)
 		case '@':
 			hlLines[i] = diffHunkStyle.Render(lines[i])
 		case 'h':
@@ -109,9 +141,11 @@ func highlightDiff(diff, filename string) string {
 }
 
 // injectBg applies a background color that persists through ANSI resets.
-// It prepends the bg escape, re-injects it after every reset, and appends a final reset.
+// It prepends the bg escape, re-injects it after every reset (including a
+// background-only reset, which search highlights end with), and appends a final reset.
 func injectBg(s, bgEsc string) string {
 	s = strings.ReplaceAll(s, "\033[0m", "\033[0m"+bgEsc)
+	s = strings.ReplaceAll(s, "\033[49m", "\033[49m"+bgEsc)
 	return bgEsc + s + "\033[0m"
 }
 
@@ -119,214 +153,223 @@ func boolPtr(b bool) *bool    { return &b }
 func stringPtr(s string) *string { return &s }
 func uintPtr(u uint) *uint    { return &u }
 
-// Tokyo Night–inspired Glamour style to match the app theme.
-var mdStyle = ansi.StyleConfig{
-	Document: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			BlockPrefix: "\n",
-			BlockSuffix: "\n",
-			Color:       stringPtr("#a9b1d6"),
-		},
-		Margin: uintPtr(2),
-	},
-	Heading: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			BlockSuffix: "\n",
-			Bold:        boolPtr(true),
+// markdownStyle builds the Glamour style from t.
+func markdownStyle(t Theme) ansi.StyleConfig {
+	return ansi.StyleConfig{
+		Document: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				BlockPrefix: "\n",
+				BlockSuffix: "\n",
+				Color:       stringPtr(t.Fg),
+			},
+			Margin: uintPtr(2),
 		},
-	},
-	H1: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			Prefix: "H1OPEN",
-			Suffix: "H1CLOSE",
-			Color:  stringPtr("#ffffff"),
-			Bold:   boolPtr(true),
-			Upper:  boolPtr(true),
+		Heading: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				BlockSuffix: "\n",
+				Bold:        boolPtr(true),
+			},
 		},
-	},
-	H2: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			Prefix: "H2OPEN",
-			Suffix: "H2CLOSE",
-			Color:  stringPtr("#c0caf5"),
-			Bold:   boolPtr(true),
+		H1: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "H1OPEN",
+				Suffix: "H1CLOSE",
+				Color:  stringPtr(t.HeadingFg[0]),
+				Bold:   boolPtr(true),
+				Upper:  boolPtr(true),
+			},
 		},
-	},
-	H3: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			Prefix: "H3OPEN",
-			Suffix: "H3CLOSE",
-			Color:  stringPtr("#a9b1d6"),
-			Bold:   boolPtr(true),
+		H2: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "H2OPEN",
+				Suffix: "H2CLOSE",
+				Color:  stringPtr(t.HeadingFg[1]),
+				Bold:   boolPtr(true),
+			},
 		},
-	},
-	H4: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			Prefix: "H4OPEN",
-			Suffix: "H4CLOSE",
-			Color:  stringPtr("#a9b1d6"),
-			Bold:   boolPtr(true),
+		H3: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "H3OPEN",
+				Suffix: "H3CLOSE",
+				Color:  stringPtr(t.HeadingFg[2]),
+				Bold:   boolPtr(true),
+			},
 		},
-	},
-	H5: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			Prefix: "H5OPEN",
-			Suffix: "H5CLOSE",
-			Color:  stringPtr("#7aa2f7"),
-			Bold:   boolPtr(true),
+		H4: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "H4OPEN",
+				Suffix: "H4CLOSE",
+				Color:  stringPtr(t.HeadingFg[3]),
+				Bold:   boolPtr(true),
+			},
 		},
-	},
-	H6: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			Prefix: "H6OPEN",
-			Suffix: "H6CLOSE",
-			Color:  stringPtr("#565f89"),
-			Bold:   boolPtr(true),
+		H5: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "H5OPEN",
+				Suffix: "H5CLOSE",
+				Color:  stringPtr(t.HeadingFg[4]),
+				Bold:   boolPtr(true),
+			},
 		},
-	},
-	BlockQuote: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			Color:  stringPtr("#565f89"),
-			Italic: boolPtr(true),
+		H6: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "H6OPEN",
+				Suffix: "H6CLOSE",
+				Color:  stringPtr(t.HeadingFg[5]),
+				Bold:   boolPtr(true),
+			},
 		},
-		Indent:      uintPtr(1),
-		IndentToken: stringPtr("│ "),
-	},
-	Paragraph: ansi.StyleBlock{},
-	List: ansi.StyleList{
-		LevelIndent: 2,
-		StyleBlock: ansi.StyleBlock{
+		BlockQuote: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
-				Color: stringPtr("#a9b1d6"),
+				Color:  stringPtr(t.FgDim),
+				Italic: boolPtr(true),
 			},
+			Indent:      uintPtr(1),
+			IndentToken: stringPtr("│ "),
 		},
-	},
-	Item: ansi.StylePrimitive{
-		BlockPrefix: "• ",
-		Color:       stringPtr("#a9b1d6"),
-	},
-	Enumeration: ansi.StylePrimitive{
-		BlockPrefix: ". ",
-		Color:       stringPtr("#7aa2f7"),
-	},
-	Task: ansi.StyleTask{
-		Ticked:   "[✓] ",
-		Unticked: "[ ] ",
-		StylePrimitive: ansi.StylePrimitive{
-			Color: stringPtr("#9ece6a"),
+		Paragraph: ansi.StyleBlock{},
+		List: ansi.StyleList{
+			LevelIndent: 2,
+			StyleBlock: ansi.StyleBlock{
+				StylePrimitive: ansi.StylePrimitive{
+					Color: stringPtr(t.Fg),
+				},
+			},
 		},
-	},
-	Strong: ansi.StylePrimitive{
-		Bold:  boolPtr(true),
-		Color: stringPtr("#c0caf5"),
-	},
-	Emph: ansi.StylePrimitive{
-		Italic: boolPtr(true),
-		Color:  stringPtr("#bb9af7"),
-	},
-	Strikethrough: ansi.StylePrimitive{
-		CrossedOut: boolPtr(true),
-		Color:      stringPtr("#565f89"),
-	},
-	HorizontalRule: ansi.StylePrimitive{
-		Color:  stringPtr("#3b4261"),
-		Format: "\nHRPLACEHOLDER\n",
-	},
-	Link: ansi.StylePrimitive{
-		Color:     stringPtr("#7dcfff"),
-		Underline: boolPtr(true),
-	},
-	LinkText: ansi.StylePrimitive{
-		Color: stringPtr("#7aa2f7"),
-		Bold:  boolPtr(true),
-	},
-	Image: ansi.StylePrimitive{
-		Color:     stringPtr("#bb9af7"),
-		Underline: boolPtr(true),
-	},
-	ImageText: ansi.StylePrimitive{
-		Color:  stringPtr("#bb9af7"),
-		Format: "🖼  {{.text}}",
-	},
-	Code: ansi.StyleBlock{
-		StylePrimitive: ansi.StylePrimitive{
-			Color:           stringPtr("#9ece6a"),
-			BackgroundColor: stringPtr("#24283b"),
-			Prefix:          " ",
-			Suffix:          " ",
+		Item: ansi.StylePrimitive{
+			BlockPrefix: "• ",
+			Color:       stringPtr(t.Fg),
 		},
-	},
-	CodeBlock: ansi.StyleCodeBlock{
-		StyleBlock: ansi.StyleBlock{
+		Enumeration: ansi.StylePrimitive{
+			BlockPrefix: ". ",
+			Color:       stringPtr(t.Blue),
+		},
+		Task: ansi.StyleTask{
+			Ticked:   "[✓] ",
+			Unticked: "[ ] ",
 			StylePrimitive: ansi.StylePrimitive{
-				Color: stringPtr("#a9b1d6"),
+				Color: stringPtr(t.Added),
 			},
-			Margin: uintPtr(2),
 		},
-		Chroma: &ansi.Chroma{
-			Text:                ansi.StylePrimitive{Color: stringPtr("#a9b1d6")},
-			Error:               ansi.StylePrimitive{Color: stringPtr("#f7768e")},
-			Comment:             ansi.StylePrimitive{Color: stringPtr("#565f89"), Italic: boolPtr(true)},
-			CommentPreproc:      ansi.StylePrimitive{Color: stringPtr("#565f89")},
-			Keyword:             ansi.StylePrimitive{Color: stringPtr("#bb9af7")},
-			KeywordReserved:     ansi.StylePrimitive{Color: stringPtr("#bb9af7")},
-			KeywordNamespace:    ansi.StylePrimitive{Color: stringPtr("#7dcfff")},
-			KeywordType:         ansi.StylePrimitive{Color: stringPtr("#2ac3de")},
-			Operator:            ansi.StylePrimitive{Color: stringPtr("#89ddff")},
-			Punctuation:         ansi.StylePrimitive{Color: stringPtr("#a9b1d6")},
-			Name:                ansi.StylePrimitive{Color: stringPtr("#c0caf5")},
-			NameBuiltin:         ansi.StylePrimitive{Color: stringPtr("#7aa2f7")},
-			NameTag:             ansi.StylePrimitive{Color: stringPtr("#f7768e")},
-			NameAttribute:       ansi.StylePrimitive{Color: stringPtr("#bb9af7")},
-			NameClass:           ansi.StylePrimitive{Color: stringPtr("#2ac3de")},
-			NameConstant:        ansi.StylePrimitive{Color: stringPtr("#ff9e64")},
-			NameDecorator:       ansi.StylePrimitive{Color: stringPtr("#ff9e64")},
-			NameFunction:        ansi.StylePrimitive{Color: stringPtr("#7aa2f7")},
-			LiteralNumber:       ansi.StylePrimitive{Color: stringPtr("#ff9e64")},
-			LiteralString:       ansi.StylePrimitive{Color: stringPtr("#9ece6a")},
-			LiteralStringEscape: ansi.StylePrimitive{Color: stringPtr("#89ddff")},
-			GenericDeleted:      ansi.StylePrimitive{Color: stringPtr("#f7768e")},
-			GenericInserted:     ansi.StylePrimitive{Color: stringPtr("#9ece6a")},
-			GenericEmph:         ansi.StylePrimitive{Italic: boolPtr(true)},
-			GenericStrong:       ansi.StylePrimitive{Bold: boolPtr(true)},
-			GenericSubheading:   ansi.StylePrimitive{Color: stringPtr("#7dcfff")},
-			Background:         ansi.StylePrimitive{BackgroundColor: stringPtr("#1a1b26")},
+		Strong: ansi.StylePrimitive{
+			Bold:  boolPtr(true),
+			Color: stringPtr(t.FgBright),
+		},
+		Emph: ansi.StylePrimitive{
+			Italic: boolPtr(true),
+			Color:  stringPtr(t.Purple),
+		},
+		Strikethrough: ansi.StylePrimitive{
+			CrossedOut: boolPtr(true),
+			Color:      stringPtr(t.FgDim),
+		},
+		HorizontalRule: ansi.StylePrimitive{
+			Color:  stringPtr(t.BorderDim),
+			Format: "\nHRPLACEHOLDER\n",
+		},
+		Link: ansi.StylePrimitive{
+			Color:     stringPtr(t.Cyan),
+			Underline: boolPtr(true),
+		},
+		LinkText: ansi.StylePrimitive{
+			Color: stringPtr(t.Blue),
+			Bold:  boolPtr(true),
+		},
+		Image: ansi.StylePrimitive{
+			Color:     stringPtr(t.Purple),
+			Underline: boolPtr(true),
 		},
-	},
-	Table: ansi.StyleTable{
-		StyleBlock: ansi.StyleBlock{
+		ImageText: ansi.StylePrimitive{
+			Color:  stringPtr(t.Purple),
+			Format: "🖼  {{.text}}",
+		},
+		Code: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
-				Color: stringPtr("#a9b1d6"),
+				Color:           stringPtr(t.Added),
+				BackgroundColor: stringPtr(t.Surface),
+				Prefix:          " ",
+				Suffix:          " ",
+			},
+		},
+		CodeBlock: ansi.StyleCodeBlock{
+			StyleBlock: ansi.StyleBlock{
+				StylePrimitive: ansi.StylePrimitive{
+					Color: stringPtr(t.Fg),
+				},
+				Margin: uintPtr(2),
+			},
+			Chroma: &ansi.Chroma{
+				Text:                ansi.StylePrimitive{Color: stringPtr(t.Fg)},
+				Error:               ansi.StylePrimitive{Color: stringPtr(t.Deleted)},
+				Comment:             ansi.StylePrimitive{Color: stringPtr(t.FgDim), Italic: boolPtr(true)},
+				CommentPreproc:      ansi.StylePrimitive{Color: stringPtr(t.FgDim)},
+				Keyword:             ansi.StylePrimitive{Color: stringPtr(t.Purple)},
+				KeywordReserved:     ansi.StylePrimitive{Color: stringPtr(t.Purple)},
+				KeywordNamespace:    ansi.StylePrimitive{Color: stringPtr(t.Cyan)},
+				KeywordType:         ansi.StylePrimitive{Color: stringPtr(t.Teal)},
+				Operator:            ansi.StylePrimitive{Color: stringPtr(t.Sky)},
+				Punctuation:         ansi.StylePrimitive{Color: stringPtr(t.Fg)},
+				Name:                ansi.StylePrimitive{Color: stringPtr(t.FgBright)},
+				NameBuiltin:         ansi.StylePrimitive{Color: stringPtr(t.Blue)},
+				NameTag:             ansi.StylePrimitive{Color: stringPtr(t.Deleted)},
+				NameAttribute:       ansi.StylePrimitive{Color: stringPtr(t.Purple)},
+				NameClass:           ansi.StylePrimitive{Color: stringPtr(t.Teal)},
+				NameConstant:        ansi.StylePrimitive{Color: stringPtr(t.Orange)},
+				NameDecorator:       ansi.StylePrimitive{Color: stringPtr(t.Orange)},
+				NameFunction:        ansi.StylePrimitive{Color: stringPtr(t.Blue)},
+				LiteralNumber:       ansi.StylePrimitive{Color: stringPtr(t.Orange)},
+				LiteralString:       ansi.StylePrimitive{Color: stringPtr(t.Added)},
+				LiteralStringEscape: ansi.StylePrimitive{Color: stringPtr(t.Sky)},
+				GenericDeleted:      ansi.StylePrimitive{Color: stringPtr(t.Deleted)},
+				GenericInserted:     ansi.StylePrimitive{Color: stringPtr(t.Added)},
+				GenericEmph:         ansi.StylePrimitive{Italic: boolPtr(true)},
+				GenericStrong:       ansi.StylePrimitive{Bold: boolPtr(true)},
+				GenericSubheading:   ansi.StylePrimitive{Color: stringPtr(t.Cyan)},
+				Background:         ansi.StylePrimitive{BackgroundColor: stringPtr(t.Bg)},
 			},
 		},
-		CenterSeparator: stringPtr("┼"),
-		ColumnSeparator: stringPtr("│"),
-		RowSeparator:    stringPtr("─"),
-	},
-	DefinitionTerm: ansi.StylePrimitive{
-		Color: stringPtr("#7aa2f7"),
-		Bold:  boolPtr(true),
-	},
-	DefinitionDescription: ansi.StylePrimitive{
-		Color:       stringPtr("#a9b1d6"),
-		BlockPrefix: "  ",
-	},
+		Table: ansi.StyleTable{
+			StyleBlock: ansi.StyleBlock{
+				StylePrimitive: ansi.StylePrimitive{
+					Color: stringPtr(t.Fg),
+				},
+			},
+			CenterSeparator: stringPtr("┼"),
+			ColumnSeparator: stringPtr("│"),
+			RowSeparator:    stringPtr("─"),
+		},
+		DefinitionTerm: ansi.StylePrimitive{
+			Color: stringPtr(t.Blue),
+			Bold:  boolPtr(true),
+		},
+		DefinitionDescription: ansi.StylePrimitive{
+			Color:       stringPtr(t.Fg),
+			BlockPrefix: "  ",
+		},
+	}
 }
 
 var (
-	hrLineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#3b4261"))
+	mdStyle     ansi.StyleConfig
+	hrLineStyle lipgloss.Style
 
 	// Heading background styles — gradient from bright to dim.
-	headingStyles = map[string]lipgloss.Style{
-		"H1": lipgloss.NewStyle().Background(lipgloss.Color("#7aa2f7")).Foreground(lipgloss.Color("#ffffff")).Bold(true),
-		"H2": lipgloss.NewStyle().Background(lipgloss.Color("#3d59a1")).Foreground(lipgloss.Color("#c0caf5")).Bold(true),
-		"H3": lipgloss.NewStyle().Background(lipgloss.Color("#2e3a5e")).Foreground(lipgloss.Color("#a9b1d6")).Bold(true),
-		"H4": lipgloss.NewStyle().Background(lipgloss.Color("#283350")).Foreground(lipgloss.Color("#a9b1d6")).Bold(true),
-		"H5": lipgloss.NewStyle().Background(lipgloss.Color("#24283b")).Foreground(lipgloss.Color("#7aa2f7")).Bold(true),
-		"H6": lipgloss.NewStyle().Background(lipgloss.Color("#1f2335")).Foreground(lipgloss.Color("#565f89")).Bold(true),
-	}
+	headingStyles map[string]lipgloss.Style
 )
 
+// buildMarkdownStyles derives the markdown renderer styles from t.
+func buildMarkdownStyles(t Theme) {
+	mdStyle = markdownStyle(t)
+	hrLineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.BorderDim))
+	headingStyles = map[string]lipgloss.Style{}
+	for i := range t.HeadingBg {
+		headingStyles[fmt.Sprintf("H%d", i+1)] = lipgloss.NewStyle().
+			Background(lipgloss.Color(t.HeadingBg[i])).
+			Foreground(lipgloss.Color(t.HeadingFg[i])).
+			Bold(true)
+	}
+}
+
 func renderMarkdown(content string, width int) string {
 	r, err := glamour.NewTermRenderer(
 		glamour.WithStyles(mdStyle),
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// logLimit caps how many commits the history view loads.
+const logLimit = 500
+
+type logLoadedMsg struct {
+	commits []commitEntry
+	err     error
+}
+
+type revFilesMsg struct {
+	commit commitEntry
+	files  []fileEntry
+	err    error
+}
+
+func loadLog() tea.Cmd {
+	return func() tea.Msg {
+		commits, err := getLog(logLimit)
+		return logLoadedMsg{commits: commits, err: err}
+	}
+}
+
+func loadRevFiles(c commitEntry) tea.Cmd {
+	return func() tea.Msg {
+		files, err := getCommitFiles(c.hash)
+		return revFilesMsg{commit: c, files: files, err: err}
+	}
+}
+
+// loadRevContent is loadFileContent for a file as committed in rev: the
+// diff rev introduced, or the file at rev with its changed lines marked.
+func loadRevContent(rev, filename string, diffMode, mdPreview bool, status string, seq, width int) tea.Cmd {
+	return func() tea.Msg {
+		diff, err := getCommitDiff(rev, filename)
+		if err != nil {
+			return fileContentMsg{err: err, filename: filename, seq: seq}
+		}
+		if (diffMode || status == "D") && strings.TrimSpace(diff) != "" {
+			return fileContentMsg{content: highlightDiff(diff, filename), filename: filename, seq: seq}
+		}
+		if status == "D" {
+			return fileContentMsg{content: "(file deleted)", filename: filename, seq: seq}
+		}
+
+		content, err := getFileAtRev(rev, filename)
+		if err != nil {
+			return fileContentMsg{err: err, filename: filename, seq: seq}
+		}
+		rendered, highlighted := renderFileContent(content, filename, mdPreview, width)
+		msg := fileContentMsg{content: rendered, filename: filename, seq: seq}
+		if highlighted {
+			msg.changedLines = parseDiffChangedLines(diff)
+		}
+		return msg
+	}
+}
+
+// logDelegate renders one commit per row: hash, age, author and subject.
+type logDelegate struct{}
+
+func (d logDelegate) Height() int                             { return 1 }
+func (d logDelegate) Spacing() int                            { return 0 }
+func (d logDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d logDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	c, ok := item.(commitEntry)
+	if !ok {
+		return
+	}
+	isSelected := index == m.Index()
+	maxWidth := m.Width()
+
+	prefix := "  "
+	if isSelected {
+		prefix = cursorStyle.Render("> ")
+	}
+
+	const authorWidth = 16
+	author := ansi.Truncate(c.author, authorWidth, "…")
+	author += strings.Repeat(" ", authorWidth-lipgloss.Width(author))
+	age := fmt.Sprintf("%-8s", relativeTime(c.when, time.Now()))
+
+	hashStyle, ageStyle, authorStyle, subjectStyle := logHashStyle, logDateStyle, logAuthorStyle, logSubjectStyle
+	if isSelected {
+		hashStyle = hashStyle.Background(colorHighlight)
+		ageStyle = ageStyle.Background(colorHighlight)
+		authorStyle = authorStyle.Background(colorHighlight)
+		subjectStyle = subjectStyle.Background(colorHighlight)
+	}
+	gap := " "
+	if isSelected {
+		gap = selectedRowStyle.Render(" ")
+	}
+
+	row := prefix + hashStyle.Render(c.short) + gap + ageStyle.Render(age) + gap + authorStyle.Render(author) + gap
+	subjectBudget := maxWidth - lipgloss.Width(row)
+	if subjectBudget < 10 {
+		subjectBudget = 10
+	}
+	row += subjectStyle.Render(ansi.Truncate(c.subject, subjectBudget, "…"))
+
+	if isSelected {
+		if rowLen := lipgloss.Width(row); rowLen < maxWidth {
+			row += selectedRowStyle.Render(strings.Repeat(" ", maxWidth-rowLen))
+		}
+	}
+	fmt.Fprint(w, row)
+}
+
+// relativeTime formats t as a short age like "5m ago", falling back to the
+// date for anything older than a month.
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// openLog switches to the history view.
+func (m model) openLog() (tea.Model, tea.Cmd) {
+	m.currentView = logView
+	return m, loadLog()
+}
+
+// closeRev leaves the commit being browsed and goes back to its log.
+func (m model) closeRev() (tea.Model, tea.Cmd) {
+	m.logCommit = nil
+	m.treeMode = m.allFiles
+	m.list.ResetFilter()
+	m.list.SetItems(nil)
+	m.currentView = logView
+	return m, tea.Batch(loadFiles(m.allFiles, m.base), loadLog())
+}
+
+// updateLog handles keys in the history view.
+func (m model) updateLog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Back):
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.logList.FilterState() == list.FilterApplied {
+			m.logList.ResetFilter()
+			return m, nil
+		}
+		m.currentView = fileListView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Open):
+		c, ok := m.logList.SelectedItem().(commitEntry)
+		if !ok {
+			return m, nil
+		}
+		return m, loadRevFiles(c)
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m, loadLog()
+
+	case key.Matches(msg, m.keys.HalfPageDown):
+		_, innerH := m.innerSize()
+		idx := m.logList.Index() + innerH/2
+		if max := len(m.logList.VisibleItems()) - 1; idx > max {
+			idx = max
+		}
+		m.logList.Select(idx)
+		return m, nil
+
+	case key.Matches(msg, m.keys.HalfPageUp):
+		_, innerH := m.innerSize()
+		idx := m.logList.Index() - innerH/2
+		if idx < 0 {
+			idx = 0
+		}
+		m.logList.Select(idx)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.logList, cmd = m.logList.Update(msg)
+	return m, cmd
+}
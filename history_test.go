@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLog(t *testing.T) {
+	out := "abc123full\x00abc123\x00Ada Lovelace\x001700000000\x00Add engine\n" +
+		"def456full\x00def456\x00Bot\x001700000100\x00Subject with \x00 nul\n"
+	commits := parseLog(out)
+	if len(commits) != 2 {
+		t.Fatalf("got %d commits, want 2", len(commits))
+	}
+	c := commits[0]
+	if c.hash != "abc123full" || c.short != "abc123" || c.author != "Ada Lovelace" || c.subject != "Add engine" {
+		t.Errorf("commit 0 = %+v", c)
+	}
+	if !c.when.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("when = %v", c.when)
+	}
+	if commits[1].subject != "Subject with \x00 nul" {
+		t.Errorf("subject = %q", commits[1].subject)
+	}
+	if got := parseLog(""); len(got) != 0 {
+		t.Errorf("empty log parsed as %v", got)
+	}
+}
+
+func TestParseNameStatus(t *testing.T) {
+	out := "M\x00a.go\x00R087\x00old.go\x00new.go\x00A\x00dir/b.txt\x00D\x00gone.md\x00T\x00link\x00"
+	files := parseNameStatus(out)
+	want := []fileEntry{
+		{status: "M", path: "a.go"},
+		{status: "R", path: "new.go"},
+		{status: "A", path: "dir/b.txt"},
+		{status: "D", path: "gone.md"},
+		{status: "M", path: "link"},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("got %+v", files)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("file %d = %+v, want %+v", i, files[i], want[i])
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	cases := map[time.Duration]string{
+		10 * time.Second:    "just now",
+		5 * time.Minute:     "5m ago",
+		3 * time.Hour:       "3h ago",
+		49 * time.Hour:      "2d ago",
+		45 * 24 * time.Hour: "2024-03-26",
+	}
+	for ago, want := range cases {
+		if got := relativeTime(now.Add(-ago), now); got != want {
+			t.Errorf("relativeTime(-%v) = %q, want %q", ago, got, want)
+		}
+	}
+}
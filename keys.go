@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyMap holds every remappable binding. Names in the config file are the
+// snake_case keys of keyActions.
+type keyMap struct {
+	Quit         key.Binding
+	Back         key.Binding
+	Open         key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	Left         key.Binding
+	Right        key.Binding
+	Filter       key.Binding
+	SearchNext   key.Binding
+	SearchPrev   key.Binding
+	Refresh      key.Binding
+	Help         key.Binding
+
+	Diff      key.Binding
+	SplitDiff key.Binding
+	Preview   key.Binding
+	Tree      key.Binding
+	Log       key.Binding
+	Base      key.Binding
+	Grep      key.Binding
+	Timeline  key.Binding
+	Sort      key.Binding
+	Group     key.Binding
+
+	QuickFix     key.Binding
+	BlockEdit    key.Binding
+	OpenEditor   key.Binding
+	Blame        key.Binding
+	Restore      key.Binding
+	Undo         key.Binding
+	Redo         key.Binding
+	EditHistory  key.Binding
+	Commit       key.Binding
+	StageHunk    key.Binding
+	UnstageHunk  key.Binding
+	DiscardHunk  key.Binding
+	SelectLines  key.Binding
+	ToggleStaged key.Binding
+
+	MergeBase key.Binding // base picker
+
+	GrepUntracked key.Binding // grep view
+
+	EditSave       key.Binding // block editor
+	EditInsertLine key.Binding
+	EditDeleteLine key.Binding
+
+	CommitSubmit  key.Binding
+	CommitAmend   key.Binding
+	CommitSignoff key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Quit:         key.NewBinding(key.WithKeys("q", "ctrl+c")),
+		Back:         key.NewBinding(key.WithKeys("esc")),
+		Open:         key.NewBinding(key.WithKeys("enter")),
+		Up:           key.NewBinding(key.WithKeys("k", "up")),
+		Down:         key.NewBinding(key.WithKeys("j", "down")),
+		HalfPageUp:   key.NewBinding(key.WithKeys("shift+up")),
+		HalfPageDown: key.NewBinding(key.WithKeys("shift+down")),
+		Top:          key.NewBinding(key.WithKeys("g")),
+		Bottom:       key.NewBinding(key.WithKeys("G")),
+		Left:         key.NewBinding(key.WithKeys("h", "left")),
+		Right:        key.NewBinding(key.WithKeys("l", "right")),
+		Filter:       key.NewBinding(key.WithKeys("/")),
+		SearchNext:   key.NewBinding(key.WithKeys("n")),
+		SearchPrev:   key.NewBinding(key.WithKeys("N")),
+		Refresh:      key.NewBinding(key.WithKeys("r")),
+		Help:         key.NewBinding(key.WithKeys("?")),
+
+		Diff:      key.NewBinding(key.WithKeys("d")),
+		SplitDiff: key.NewBinding(key.WithKeys("|")),
+		Preview:   key.NewBinding(key.WithKeys("p")),
+		Tree:      key.NewBinding(key.WithKeys("t")),
+		Log:       key.NewBinding(key.WithKeys("L")),
+		Base:      key.NewBinding(key.WithKeys("B")),
+		Grep:      key.NewBinding(key.WithKeys("F")),
+		Timeline:  key.NewBinding(key.WithKeys("T")),
+		Sort:      key.NewBinding(key.WithKeys("O")),
+		Group:     key.NewBinding(key.WithKeys("=")),
+
+		QuickFix:     key.NewBinding(key.WithKeys("e")),
+		BlockEdit:    key.NewBinding(key.WithKeys("E")),
+		OpenEditor:   key.NewBinding(key.WithKeys("o")),
+		Blame:        key.NewBinding(key.WithKeys("b")),
+		Restore:      key.NewBinding(key.WithKeys("R")),
+		Undo:         key.NewBinding(key.WithKeys("z")),
+		Redo:         key.NewBinding(key.WithKeys("Z")),
+		EditHistory:  key.NewBinding(key.WithKeys("H")),
+		Commit:       key.NewBinding(key.WithKeys("c")),
+		StageHunk:    key.NewBinding(key.WithKeys("s")),
+		UnstageHunk:  key.NewBinding(key.WithKeys("u")),
+		DiscardHunk:  key.NewBinding(key.WithKeys("x")),
+		SelectLines:  key.NewBinding(key.WithKeys("v")),
+		ToggleStaged: key.NewBinding(key.WithKeys("S")),
+
+		MergeBase: key.NewBinding(key.WithKeys("m")),
+
+		GrepUntracked: key.NewBinding(key.WithKeys("U")),
+
+		EditSave:       key.NewBinding(key.WithKeys("ctrl+s")),
+		EditInsertLine: key.NewBinding(key.WithKeys("ctrl+o")),
+		EditDeleteLine: key.NewBinding(key.WithKeys("ctrl+x")),
+
+		CommitSubmit:  key.NewBinding(key.WithKeys("ctrl+s")),
+		CommitAmend:   key.NewBinding(key.WithKeys("alt+a")),
+		CommitSignoff: key.NewBinding(key.WithKeys("alt+s")),
+	}
+}
+
+// keyActions maps config names to bindings in k.
+func (k *keyMap) keyActions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":             &k.Quit,
+		"back":             &k.Back,
+		"open":             &k.Open,
+		"up":               &k.Up,
+		"down":             &k.Down,
+		"half_page_up":     &k.HalfPageUp,
+		"half_page_down":   &k.HalfPageDown,
+		"top":              &k.Top,
+		"bottom":           &k.Bottom,
+		"left":             &k.Left,
+		"right":            &k.Right,
+		"filter":           &k.Filter,
+		"search_next":      &k.SearchNext,
+		"search_prev":      &k.SearchPrev,
+		"refresh":          &k.Refresh,
+		"help":             &k.Help,
+		"diff":             &k.Diff,
+		"split_diff":       &k.SplitDiff,
+		"preview":          &k.Preview,
+		"tree":             &k.Tree,
+		"log":              &k.Log,
+		"base":             &k.Base,
+		"grep":             &k.Grep,
+		"timeline":         &k.Timeline,
+		"sort":             &k.Sort,
+		"group":            &k.Group,
+		"quick_fix":        &k.QuickFix,
+		"block_edit":       &k.BlockEdit,
+		"open_editor":      &k.OpenEditor,
+		"blame":            &k.Blame,
+		"restore":          &k.Restore,
+		"undo":             &k.Undo,
+		"redo":             &k.Redo,
+		"edit_history":     &k.EditHistory,
+		"commit":           &k.Commit,
+		"stage_hunk":       &k.StageHunk,
+		"unstage_hunk":     &k.UnstageHunk,
+		"discard_hunk":     &k.DiscardHunk,
+		"select_lines":     &k.SelectLines,
+		"toggle_staged":    &k.ToggleStaged,
+		"merge_base":       &k.MergeBase,
+		"grep_untracked":   &k.GrepUntracked,
+		"edit_save":        &k.EditSave,
+		"edit_insert_line": &k.EditInsertLine,
+		"edit_delete_line": &k.EditDeleteLine,
+		"commit_submit":    &k.CommitSubmit,
+		"commit_amend":     &k.CommitAmend,
+		"commit_signoff":   &k.CommitSignoff,
+	}
+}
+
+// apply replaces the keys of each named action.
+func (k *keyMap) apply(overrides map[string][]string) error {
+	actions := k.keyActions()
+	var unknown []string
+	for name, keys := range overrides {
+		b, ok := actions[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if len(keys) == 0 {
+			b.SetEnabled(false)
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetEnabled(true)
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown key action(s): %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// keyLabels are display names for keys in the help overlay and command bar.
+var keyLabels = map[string]string{
+	"up":         "↑",
+	"down":       "↓",
+	"left":       "←",
+	"right":      "→",
+	"shift+up":   "Shift-↑",
+	"shift+down": "Shift-↓",
+}
+
+// keyHelp renders the keys of one or more bindings for display, interleaving
+// them so related bindings read in pairs ("j/k/↓/↑").
+func keyHelp(bindings ...key.Binding) string {
+	var parts []string
+	for i := 0; ; i++ {
+		added := false
+		for _, b := range bindings {
+			keys := b.Keys()
+			if b.Enabled() && i < len(keys) {
+				k := keys[i]
+				if label, ok := keyLabels[k]; ok {
+					k = label
+				}
+				parts = append(parts, k)
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// firstKey returns the primary key of b for compact hints.
+func firstKey(b key.Binding) string {
+	if keys := b.Keys(); b.Enabled() && len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,18 +9,46 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
 )
 
-var workDir string
+var (
+	workDir   string
+	forcePoll bool // --poll: skip the filesystem watcher
+)
 
 func main() {
-	if len(os.Args) > 1 {
-		abs, err := filepath.Abs(os.Args[1])
+	var base string
+	flag.StringVar(&base, "base", "", "compare the worktree against this ref (append ... for the merge-base with HEAD)")
+	var themeName string
+	flag.StringVar(&themeName, "theme", "", "color theme (overrides the config file)")
+	flag.BoolVar(&forcePoll, "poll", false, "poll git status instead of watching the filesystem")
+	var printMode, printDiffs bool
+	flag.BoolVar(&printMode, "print", false, "print the changed files, or the file given as path, to stdout and exit")
+	flag.BoolVar(&printMode, "once", false, "same as --print")
+	flag.BoolVar(&printDiffs, "diff", false, "with --print, print highlighted diffs instead")
+	var colorMode string
+	flag.StringVar(&colorMode, "color", "auto", "with --print: auto (terminal and no NO_COLOR), always or never")
+	var eventsFormat string
+	flag.StringVar(&eventsFormat, "events", "", "stream change events to stdout instead of starting the TUI (json: one object per line)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: git-owl [flags] [path]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var file string // a file given as path, printed by --print
+	if flag.NArg() > 0 {
+		abs, err := filepath.Abs(flag.Arg(0))
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 		workDir = abs
+		if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+			file = abs
+			workDir = filepath.Dir(abs)
+		}
 	} else {
 		wd, err := os.Getwd()
 		if err != nil {
@@ -36,9 +65,68 @@ func main() {
 	if out, err := cmd.Output(); err == nil {
 		workDir = strings.TrimSpace(string(out))
 	}
+	if file != "" {
+		// The repo root comes back with symlinks resolved
+		if resolved, err := filepath.EvalSymlinks(file); err == nil {
+			file = resolved
+		}
+		if rel, err := filepath.Rel(workDir, file); err == nil {
+			file = filepath.ToSlash(rel)
+		}
+	}
+
+	c, err := loadConfig(workDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg = c
+	if themeName != "" {
+		cfg.Theme.Name = themeName
+	}
+	t, err := loadTheme(cfg.Theme.Name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	applyTheme(t)
+	if cfg.Highlight.Style != "" {
+		setHighlightStyle(cfg.Highlight.Style)
+	}
+
+	if base != "" {
+		if _, err := resolveBase(base); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if eventsFormat != "" {
+		err := fmt.Errorf("--events only supports json, not %q", eventsFormat)
+		if eventsFormat == "json" {
+			err = runEvents(os.Stdout, base)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if printMode {
+		color, err := printColor(colorMode, term.IsTerminal(int(os.Stdout.Fd())))
+		if err == nil {
+			err = runPrint(printOptions{file: file, diff: printDiffs, base: base, color: color})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	m := initialModel()
+	m.base = base
 
 	p := tea.NewProgram(
-		initialModel(),
+		m,
 		tea.WithAltScreen(),
 	)
 
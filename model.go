@@ -1,12 +1,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,10 +23,20 @@ type view int
 const (
     fileListView view = iota
     fileViewerView
+    commitView
+    logView
+    baseView
+    editsView
+    grepView
+    timelineView
+    restoreView
 )
 
 // Messages
 type filesLoadedMsg struct {
+	all      bool   // listed every tracked file
+	base     string // compared against this base
+	origin   string // commit the files are compared against, with snapshots
 	files    []fileEntry
 	branch   string
 	err      error
@@ -35,12 +49,13 @@ type fileContentMsg struct {
 	seq          int
 	err          error
 	changedLines map[int]bool // new-file line numbers with changes (for gutter indicators)
+	diff         string       // raw unified diff when content is a diff (for staging)
 }
 
 type tickMsg time.Time
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+func tickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -48,6 +63,8 @@ func tickCmd() tea.Cmd {
 // Custom list delegate for colored badge rows.
 type fileDelegate struct {
 	recentFiles map[string]bool
+	stats       bool // show each file's line counts and churn bar
+	mostChurn   int  // churn of the busiest file, which fills its bar
 }
 
 func (d fileDelegate) Height() int                             { return 1 }
@@ -55,6 +72,10 @@ func (d fileDelegate) Spacing() int                            { return 0 }
 func (d fileDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 
 func (d fileDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if h, ok := item.(sectionHeader); ok {
+		fmt.Fprint(w, "  "+sectionHeaderStyle.Render(h.title)+headerDimStyle.Render(fmt.Sprintf(" %d", h.count)))
+		return
+	}
 	f, ok := item.(fileEntry)
 	if !ok {
 		return
@@ -64,7 +85,7 @@ func (d fileDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 	isRecent := d.recentFiles[f.path]
 	maxWidth := m.Width()
 
-	// Prefix: cursor/marker (2) + badge (3) + space (1) = 6 chars
+	// Prefix: cursor/marker (2) + badge (3) + space (1) + XY columns (2) + space (1) = 9 chars
 	var prefix string
 	if isRecent {
 		prefix = recentMarkerStyle.Render("✦ ")
@@ -74,12 +95,30 @@ func (d fileDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		prefix = "  "
 	}
 	badge := statusBadgeStyle(f.status).Render(statusLabel(f.status))
+	columns := renderStatusColumns(f)
 
 	// Split path into dir + filename
 	dir, file := splitPath(f.path)
 
-	// Truncate path to fit: maxWidth - 6 (prefix+badge+space)
-	pathBudget := maxWidth - 7
+	// Line counts and churn bar, right-aligned
+	var stat string
+	if d.stats {
+		var bg lipgloss.TerminalColor
+		if isSelected || isRecent {
+			bg = colorHighlight
+		}
+		gap := " "
+		if bg != nil {
+			gap = selectedRowStyle.Render(" ")
+		}
+		stat = renderStat(f.stat, bg) + gap + renderStatBar(f.stat, d.mostChurn, bg)
+	}
+
+	// Truncate path to fit: maxWidth - 9 (prefix+badge+columns+spaces), less the stat
+	pathBudget := maxWidth - 10
+	if stat != "" {
+		pathBudget -= lipgloss.Width(stat) + 2
+	}
 	if pathBudget < 10 {
 		pathBudget = 10
 	}
@@ -118,7 +157,17 @@ func (d fileDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		}
 	}
 
-	row := prefix + badge + " " + pathStr
+	row := prefix + badge + " " + columns + " " + pathStr
+
+	if stat != "" {
+		if gap := maxWidth - lipgloss.Width(row) - lipgloss.Width(stat); gap > 0 {
+			pad := strings.Repeat(" ", gap)
+			if isSelected || isRecent {
+				pad = selectedRowStyle.Render(pad)
+			}
+			row += pad + stat
+		}
+	}
 
 	if isSelected || isRecent {
 		rowLen := lipgloss.Width(row)
@@ -131,6 +180,25 @@ func (d fileDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 	fmt.Fprint(w, row)
 }
 
+// renderStatusColumns renders the porcelain index and worktree columns like
+// git status does: staged changes in green, unstaged ones in red.
+func renderStatusColumns(f fileEntry) string {
+	if f.status == "??" {
+		return untrackedColumnStyle.Render("??")
+	}
+	col := func(c byte, style lipgloss.Style) string {
+		if c == 0 || c == ' ' {
+			return emptyColumnStyle.Render("·")
+		}
+		return style.Render(string(c))
+	}
+	if f.index == 0 && f.worktree == 0 {
+		// Not from git status (e.g. all-files mode)
+		return "  "
+	}
+	return col(f.index, stagedColumnStyle) + col(f.worktree, unstagedColumnStyle)
+}
+
 func splitPath(path string) (dir, file string) {
 	idx := strings.LastIndex(path, "/")
 	if idx == -1 {
@@ -141,11 +209,13 @@ func splitPath(path string) (dir, file string) {
 
 type model struct {
 	currentView view
+	keys        keyMap
 	list        list.Model
 	viewport    viewport.Model
 	width       int
 	height      int
 	diffMode    bool
+	splitDiff   bool // side-by-side diffs when the panel is wide enough
 	mdPreview   bool
 	allFiles    bool
 	currentFile string
@@ -155,16 +225,28 @@ type model struct {
 	ready       bool
 
 	// Animation
-	spinner  spinnerState
-	owl      owlState
-	showHelp bool // '?' toggles
+	spinner    spinnerState
+	owl        owlState
+	showHelp   bool // '?' toggles
+	helpScroll int  // first help line shown when it doesn't fit
 
 	// Snapshot diffing & events
 	prevSnapshot snapshot
 	events       eventsRing
 	recentFiles  map[string]bool // paths with recent changes (for row ✦ markers)
 
-	// Cursor line (0-based file line index)
+	// Session timeline: every change event since startup, oldest first
+	timeline         []changeEvent
+	timelineList     list.Model
+	timelinePrevView view
+	travel           *travelDiff // past state the viewer shows, nil for the worktree
+
+	// Restore picker: states a file can be put back to
+	restoreList     list.Model
+	restorePath     string
+	restorePrevView view
+
+	// Cursor line (0-based display line: file line, diff line, or split row)
 	cursorLine int
 
 	// Quick-fix inline editing
@@ -174,27 +256,133 @@ type model struct {
 	quickFixLine       int             // 0-based real file line being edited
 	quickFixCursorLine int             // 0-based viewport content line (for overlay positioning)
 	quickFixInput      textinput.Model // text input widget
+	fixBase            editBase        // file and lines the open quick fix started from
+
+	// Block edit: multi-line quick-fix replacing file lines blockFrom..blockTo
+	blockEdit  bool
+	blockFrom  int            // 0-based first file line being replaced
+	blockTo    int            // 0-based last file line, inclusive
+	blockTabs  bool           // block was tab-indented; new lines get tabs on save
+	blockRow   int            // viewer line the editor is drawn from
+	blockInput textarea.Model // editor widget
+
+	// In-file search: the prompt, and the last pattern entered
+	searching   bool
+	searchInput textinput.Model
+	search      *regexp.Regexp // nil when nothing is highlighted
+	searchQuery string
+
+	// Blame column in the file view
+	blame      bool
+	blameLines []blameLine // one per file line; nil until loaded
+	blameFor   string      // file blameLines belong to
 
 	// Horizontal scroll
 	hScroll      int          // current horizontal offset in visible columns
 	rawContent   string       // unshifted file content for re-applying offset
 	changedLines map[int]bool // new-file line numbers with changes (gutter indicators)
 
+	// Staging (diff mode)
+	rawDiff      string         // unhighlighted diff behind rawContent, "" when not a diff
+	diffStaged   bool           // rawDiff is the index diff (--cached)
+	confirm      *confirmPrompt // pending y/n prompt, nil when none
+	visual       bool           // line selection active ('v')
+	visualAnchor int            // display line where the selection started
+
+	// Commit composer
+	commitInput    textarea.Model
+	commitFiles    []fileEntry // staged files
+	commitAmend    bool
+	commitSignoff  bool
+	commitPrevView view // view to return to on esc / after committing
+
+	// Base ref: compare the worktree against this instead of HEAD and the
+	// index ("" for uncommitted changes, "<ref>..." for the merge-base)
+	base     string
+	baseList list.Model
+
+	// History
+	logList   list.Model
+	logCommit *commitEntry // commit whose files the list shows, nil for the worktree
+
+	// Quick-fix edits this session, for undo/redo and the history panel
+	edits         editHistory
+	editList      list.Model
+	editsPrevView view // view to return to when the panel closes
+
+	// Grep view: git grep results grouped by file
+	grepList      list.Model
+	grepInput     textinput.Model
+	grepping      bool // query prompt open
+	grepQuery     string
+	grepRe        *regexp.Regexp // grepQuery for highlighting; nil if Go can't compile it
+	grepUntracked bool
+	grepLoading   bool
+	grepTotal     int // matches found, before grepLimit
+	grepFiles     int
+	grepPrevView  view
+	viewerReturn  view // where esc leaves the viewer for (the file list by default)
+	viewerDiff    bool // diffMode to put back on leaving for viewerReturn
+
+	// Status message shown in the command bar
+	flash      string
+	flashErr   bool
+	flashUntil time.Time
+
 	// Tree view
 	treeMode bool
 	treeRoot *treeNode
 	treeCwd  *treeNode // current directory being displayed
 
+	// Order and sections of the changed-file list
+	fileSort  sortMode
+	fileGroup groupMode
+
 	// Header pulse
 	headerPulse int // frames remaining (decremented by animTick)
 
 	// Metrics
 	lastScanTime time.Duration // how long the last git status call took
 	lastScanAt   time.Time     // when last scan completed
+
+	// Refresh
+	watcher         *fsWatcher    // nil when polling
+	refreshInterval time.Duration // poll interval (long safety net while watching)
 }
 
 func initialModel() model {
-	l := list.New(nil, fileDelegate{}, 0, 0)
+	keys := cfg.keyMap()
+	// Already validated by decodeConfig
+	fileSort, _ := parseSortMode(cfg.Startup.Sort)
+	fileGroup, _ := parseGroupMode(cfg.Startup.Group)
+	return model{
+		currentView:  fileListView,
+		list:         newItemList(fileDelegate{}, keys),
+		logList:      newItemList(logDelegate{}, keys),
+		baseList:     newItemList(refDelegate{}, keys),
+		editList:     newItemList(editDelegate{}, keys),
+		grepList:     newItemList(grepDelegate{}, keys),
+		timelineList: newItemList(timelineDelegate{}, keys),
+		restoreList:  newItemList(restoreDelegate{}, keys),
+		keys:         keys,
+		branch:       "?",
+		owl:          newOwlState(),
+		events:       newEventsRing(5),
+		recentFiles:  map[string]bool{},
+		diffMode:     cfg.Startup.Diff,
+		splitDiff:    cfg.Startup.Split,
+		allFiles:     cfg.Startup.Tree,
+		treeMode:     cfg.Startup.Tree,
+		fileSort:     fileSort,
+		fileGroup:    fileGroup,
+
+		refreshInterval: cfg.Refresh.Poll.Duration,
+	}
+}
+
+// newItemList creates a borderless, filterable list driven by keys.
+func newItemList(d list.ItemDelegate, keys keyMap) list.Model {
+	l := list.New(nil, d, 0, 0)
 	l.Title = ""
 	l.SetShowStatusBar(false)
 	l.SetShowTitle(false)
@@ -205,21 +393,26 @@ func initialModel() model {
 	l.Styles.NoItems = lipgloss.NewStyle().Foreground(colorFgDim).Padding(1, 2)
 	l.Styles.TitleBar = lipgloss.NewStyle() // remove default bottom padding
 
-	return model{
-		currentView: fileListView,
-		list:        l,
-		branch:      "?",
-		owl:         newOwlState(),
-		events:      newEventsRing(5),
-		recentFiles: map[string]bool{},
-	}
+	// Let remapped movement keys drive the list too; quitting is handled by us
+	l.KeyMap.CursorUp = keys.Up
+	l.KeyMap.CursorDown = keys.Down
+	l.KeyMap.GoToStart = keys.Top
+	l.KeyMap.GoToEnd = keys.Bottom
+	l.KeyMap.Filter = keys.Filter
+	l.KeyMap.Quit.SetEnabled(false)
+	l.KeyMap.ForceQuit.SetEnabled(false)
+	return l
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(loadFiles(false), tickCmd(), animTickCmd())
+	cmds := []tea.Cmd{loadFiles(m.allFiles, m.base), tickCmd(m.refreshInterval), animTickCmd()}
+	if cfg.Refresh.Watch && !forcePoll {
+		cmds = append(cmds, startWatchCmd())
+	}
+	return tea.Batch(cmds...)
 }
 
-func loadFiles(all bool) tea.Cmd {
+func loadFiles(all bool, base string) tea.Cmd {
 	return func() tea.Msg {
 		start := time.Now()
 		var files []fileEntry
@@ -227,11 +420,16 @@ func loadFiles(all bool) tea.Cmd {
 		if all {
 			files, err = getAllFiles()
 		} else {
-			files, err = getChangedFiles()
+			files, err = getChangedFiles(base)
 		}
 		branch := getCurrentBranch()
 		elapsed := time.Since(start)
-		return filesLoadedMsg{files: files, branch: branch, err: err, scanTime: elapsed}
+		msg := filesLoadedMsg{all: all, base: base, files: files, branch: branch, err: err, scanTime: elapsed}
+		if !all && err == nil && cfg.Snapshots.Enabled {
+			captureBlobs(files)
+			msg.origin = snapshotOrigin(base)
+		}
+		return msg
 	}
 }
 
@@ -242,21 +440,47 @@ func isPreviewable(filename string) bool {
 		strings.HasSuffix(lower, ".mermaid")
 }
 
-func loadFileContent(filename string, diffMode, mdPreview bool, status string, seq, width int) tea.Cmd {
+// loadFileContent loads filename for the viewer. In diff mode, staged picks
+// the index diff over the worktree diff; a non-empty base replaces both with
+// the diff against that base.
+func loadFileContent(filename string, diffMode, staged, mdPreview bool, status, base string, seq, width int) tea.Cmd {
 	return func() tea.Msg {
+		// Every uncommitted change, or the whole diff against base
+		fullDiff := func() (string, error) {
+			if base != "" {
+				return getDiff(filename, false, base)
+			}
+			return getHeadDiff(filename)
+		}
+
 		if diffMode && status != "??" {
-			diff, err := getDiff(filename)
+			diff, err := getDiff(filename, staged, base)
 			if err != nil {
 				return fileContentMsg{err: err, filename: filename, seq: seq}
 			}
 			if strings.TrimSpace(diff) != "" {
 				highlighted := highlightDiff(diff, filename)
-				return fileContentMsg{content: highlighted, filename: filename, seq: seq}
+				msg := fileContentMsg{content: highlighted, filename: filename, seq: seq}
+				if base == "" {
+					// Only index and worktree diffs can be staged from
+					msg.diff = diff
+				}
+				return msg
+			}
+			// This layer is clean — point at the other one if it has changes
+			if base == "" {
+				if other, err := getDiff(filename, !staged, ""); err == nil && strings.TrimSpace(other) != "" {
+					content := "(no unstaged changes — press S for the staged diff)"
+					if staged {
+						content = "(no staged changes — press S for the unstaged diff)"
+					}
+					return fileContentMsg{content: content, filename: filename, seq: seq}
+				}
 			}
 		}
 
 		if status == "D" {
-			diff, err := getDiff(filename)
+			diff, err := fullDiff()
 			if err == nil && strings.TrimSpace(diff) != "" {
 				highlighted := highlightDiff(diff, filename)
 				return fileContentMsg{content: highlighted, filename: filename, seq: seq}
@@ -269,48 +493,70 @@ func loadFileContent(filename string, diffMode, mdPreview bool, status string, s
 			return fileContentMsg{err: err, filename: filename, seq: seq}
 		}
 
-		if isBinary(content) {
-			return fileContentMsg{content: "(binary file)", filename: filename, seq: seq}
-		}
-
-		lower := strings.ToLower(filename)
-		if mdPreview && (strings.HasSuffix(lower, ".mmd") || strings.HasSuffix(lower, ".mermaid")) {
-			rendered, err := renderMermaid(content)
-			if err != nil {
-				rendered = "Mermaid render error: " + err.Error() + "\n\n" + highlightContent(content, filename)
-			}
+		rendered, highlighted := renderFileContent(content, filename, mdPreview, width)
+		if !highlighted {
 			return fileContentMsg{content: rendered, filename: filename, seq: seq}
 		}
 
-		if mdPreview && strings.HasSuffix(lower, ".md") {
-			rendered := renderMarkdownWithMermaid(content, width)
-			return fileContentMsg{content: rendered, filename: filename, seq: seq}
-		}
-
-		highlighted := highlightContent(content, filename)
-
 		// When not in diff mode, fetch diff to mark changed lines in gutter
 		var changed map[int]bool
 		if !diffMode && status != "" && status != "??" {
-			if diff, err := getDiff(filename); err == nil && strings.TrimSpace(diff) != "" {
+			if diff, err := fullDiff(); err == nil && strings.TrimSpace(diff) != "" {
 				changed = parseDiffChangedLines(diff)
 			}
 		}
 
-		return fileContentMsg{content: highlighted, filename: filename, seq: seq, changedLines: changed}
+		return fileContentMsg{content: rendered, filename: filename, seq: seq, changedLines: changed}
+	}
+}
+
+// renderFileContent formats file content for the viewer: a binary notice, a
+// mermaid or markdown preview, or syntax highlighting. highlighted reports
+// the last case, where line numbers and gutter markers apply.
+func renderFileContent(content, filename string, mdPreview bool, width int) (rendered string, highlighted bool) {
+	if isBinary(content) {
+		return "(binary file)", false
+	}
+
+	lower := strings.ToLower(filename)
+	if mdPreview && (strings.HasSuffix(lower, ".mmd") || strings.HasSuffix(lower, ".mermaid")) {
+		rendered, err := renderMermaid(content)
+		if err != nil {
+			rendered = "Mermaid render error: " + err.Error() + "\n\n" + highlightContent(content, filename)
+		}
+		return rendered, false
+	}
+
+	if mdPreview && strings.HasSuffix(lower, ".md") {
+		return renderMarkdownWithMermaid(content, width), false
 	}
+
+	return highlightContent(content, filename), true
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		wasSplit := m.splitActive()
 		m.width = msg.Width
 		m.height = msg.Height
 		innerW, innerH := m.innerSize()
 		m.list.SetSize(innerW-1, innerH)
+		m.logList.SetSize(innerW-1, innerH)
+		m.baseList.SetSize(innerW-1, innerH)
+		m.editList.SetSize(innerW-1, innerH)
+		m.grepList.SetSize(innerW-1, innerH-1) // query line
+		m.timelineList.SetSize(innerW-1, innerH)
+		m.restoreList.SetSize(innerW-1, innerH-1) // file line
 		if m.currentView == fileViewerView {
 			m.viewport.Width = innerW - 1
 			m.viewport.Height = innerH - 2 // breadcrumb + separator
+			m.remapCursor(wasSplit)
+			m.viewport.SetContent(m.viewportContent())
+		}
+		if m.currentView == commitView {
+			m.commitInput.SetWidth(innerW - 2)
+			m.commitInput.SetHeight(commitEditorHeight(innerH))
 		}
 		m.ready = true
 		return m, nil
@@ -326,7 +572,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case filesLoadedMsg:
-		if msg.err != nil {
+		// Drop errors, and lists from before a switch to all files or a new base
+		if msg.err != nil || msg.all != m.allFiles || msg.base != m.base {
 			return m, nil
 		}
 		m.branch = msg.branch
@@ -334,17 +581,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.lastScanTime = msg.scanTime
 
 		// Snapshot diffing
+		snap := newSnapshot(msg.files)
+		snap.origin = msg.origin
 		if m.prevSnapshot.files != nil {
-			changes := m.prevSnapshot.diff(msg.files)
+			changes := m.prevSnapshot.changes(snap)
 			if len(changes) > 0 {
 				m.events.push(changes)
+				m.recordTimeline(changes)
 				m.headerPulse = 6 // ~600ms at 100ms ticks
 			}
 		}
-		m.prevSnapshot = newSnapshot(msg.files)
+		m.prevSnapshot = snap
+		if m.currentView == commitView && !m.allFiles && m.base == "" {
+			m.commitFiles = stagedFiles(msg.files)
+		}
 
-		// Don't update items while user is actively filtering — it resets the filter
-		if m.list.FilterState() == list.Unfiltered {
+		// Don't update items while user is actively filtering — it resets the filter,
+		// or while the list shows a commit's files
+		if m.list.FilterState() == list.Unfiltered && m.logCommit == nil {
 			if m.treeMode {
 				cwdPath := ""
 				if m.treeCwd != nil {
@@ -360,11 +614,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.list.SetItems(childItems(m.treeCwd))
 			} else {
-				items := make([]list.Item, len(msg.files))
-				for i, f := range msg.files {
-					items[i] = f
-				}
-				m.list.SetItems(items)
+				m.setFileItems(msg.files)
 			}
 		}
 		return m, nil
@@ -376,13 +626,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		wasAutoRefresh := m.autoRefresh
 		m.autoRefresh = false
 		m.changedLines = msg.changedLines
+		m.rawDiff = msg.diff
+		if !wasAutoRefresh {
+			m.visual = false
+		}
+		prevContent := m.rawContent
 		if msg.err != nil {
 			m.rawContent = fmt.Sprintf("Error: %v", msg.err)
 		} else {
 			m.rawContent = msg.content
 		}
-		innerW, _ := m.innerSize()
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		// Content may have shrunk underneath the cursor (e.g. after staging a hunk)
+		if total := m.lineCount(); m.cursorLine >= total {
+			m.cursorLine = total - 1
+		}
+		m.viewport.SetContent(m.viewportContent())
 		if !wasAutoRefresh && !m.quickFixPending {
 			m.viewport.GotoTop()
 		}
@@ -391,38 +649,237 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.SetYOffset(m.quickFixYOffset)
 		}
 		m.currentView = fileViewerView
+		// Blame again unless a refresh found nothing new
+		if m.blameShown() && (!wasAutoRefresh || m.rawContent != prevContent) {
+			return m, m.reloadBlame()
+		}
 		return m, nil
 
 	case tickMsg:
-		cmds := []tea.Cmd{tickCmd()}
-		cmds = append(cmds, loadFiles(m.allFiles))
-		if m.currentView == fileViewerView && m.currentFile != "" && !m.quickFix {
-			m.loadSeq++
-			m.autoRefresh = true
-			item, ok := m.list.SelectedItem().(fileEntry)
-			status := ""
-			if ok {
-				status = item.status
-			}
-			innerW, _ := m.innerSize()
-			cmds = append(cmds, loadFileContent(m.currentFile, m.diffMode, m.mdPreview, status, m.loadSeq, innerW))
+		cmds := []tea.Cmd{tickCmd(m.refreshInterval)}
+		cmds = append(cmds, m.refreshCmds()...)
+		return m, tea.Batch(cmds...)
+
+	case watcherStartedMsg:
+		if msg.err != nil {
+			// Keep polling
+			return m, nil
 		}
+		m.watcher = msg.w
+		m.refreshInterval = cfg.Refresh.SafetyPoll.Duration
+		return m, waitForChange(m.watcher)
+
+	case fsChangedMsg:
+		cmds := []tea.Cmd{waitForChange(m.watcher)}
+		cmds = append(cmds, m.refreshCmds()...)
 		return m, tea.Batch(cmds...)
 
+	case logLoadedMsg:
+		if msg.err != nil {
+			m.setFlash("git log: "+msg.err.Error(), true)
+			return m, nil
+		}
+		if m.logList.FilterState() == list.Unfiltered {
+			items := make([]list.Item, len(msg.commits))
+			for i, c := range msg.commits {
+				items[i] = c
+			}
+			m.logList.SetItems(items)
+		}
+		return m, nil
+
+	case refsLoadedMsg:
+		if msg.err != nil {
+			m.setFlash(msg.err.Error(), true)
+			return m, nil
+		}
+		m.baseList.SetItems(refItems(msg.refs))
+		return m, nil
+
+	case baseChosenMsg:
+		if msg.err != nil {
+			m.setFlash(msg.err.Error(), true)
+			return m, nil
+		}
+		m.base = msg.base
+		m.currentView = fileListView
+		m.list.ResetFilter()
+		// Don't report every file of the new comparison as a change
+		m.prevSnapshot = snapshot{}
+		if m.base == "" {
+			m.setFlash("showing uncommitted changes", false)
+		} else {
+			m.setFlash("comparing against "+m.base, false)
+		}
+		return m, loadFiles(m.allFiles, m.base)
+
+	case revFilesMsg:
+		if msg.err != nil {
+			m.setFlash(msg.err.Error(), true)
+			return m, nil
+		}
+		c := msg.commit
+		m.logCommit = &c
+		m.treeMode = false
+		m.list.ResetFilter()
+		m.setFileItems(msg.files)
+		m.list.Select(0)
+		skipSectionHeader(&m.list, true)
+		m.currentView = fileListView
+		return m, nil
+
+	case commitFilesMsg:
+		if msg.err == nil {
+			m.commitFiles = msg.files
+		}
+		return m, nil
+
+	case lastCommitMsg:
+		if m.currentView == commitView && strings.TrimSpace(m.commitInput.Value()) == "" {
+			m.commitInput.SetValue(msg.message)
+		}
+		return m, nil
+
+	case commitDoneMsg:
+		if msg.err != nil {
+			m.setFlash(msg.err.Error(), true)
+			return m, nil
+		}
+		m.setFlash("committed "+msg.summary, false)
+		m.currentView = m.commitPrevView
+		m.commitInput.Reset()
+		return m, tea.Batch(loadFiles(m.allFiles, m.base), m.reloadViewer())
+
+	case quickFixSavedMsg:
+		// Show what's on disk now, whether or not the write went through
+		var cmd tea.Cmd
+		if msg.reload != nil {
+			var mdl tea.Model
+			mdl, cmd = m.Update(msg.reload)
+			m = mdl.(model)
+		}
+		var conflict *editConflict
+		switch {
+		case errors.As(msg.err, &conflict):
+			m.confirmOverwrite(conflict, msg.rec.after)
+		case msg.err != nil:
+			m.setFlash("quick fix not saved: "+msg.err.Error(), true)
+		default:
+			m.edits.record(msg.rec)
+			if msg.rec.line != m.fixBase.from {
+				m.setFlash(fmt.Sprintf("file changed while editing — fix applied at line %d", msg.rec.line+1), false)
+			}
+		}
+		return m, cmd
+
+	case editorClosedMsg:
+		if msg.err != nil {
+			m.setFlash("editor: "+msg.err.Error(), true)
+		}
+		return m, tea.Batch(loadFiles(m.allFiles, m.base), m.reloadViewer())
+
+	case editRevertedMsg:
+		if msg.err != nil {
+			m.setFlash(msg.err.Error(), true)
+			return m, nil
+		}
+		// A stale revert, overtaken by another edit, leaves the history alone
+		what := "undid"
+		if msg.redo {
+			m.edits.markRedone(msg.rec)
+			what = "redid"
+		} else {
+			m.edits.markUndone(msg.rec)
+		}
+		m.setFlash(fmt.Sprintf("%s edit at %s:%d", what, msg.rec.path, msg.rec.line+1), false)
+		if m.currentView == editsView {
+			m.editList.SetItems(m.edits.entries())
+		}
+		return m, tea.Batch(loadFiles(m.allFiles, m.base), m.reloadViewer())
+
+	case grepDoneMsg:
+		return m.grepLoaded(msg)
+
+	case blameLoadedMsg:
+		return m.blameLoaded(msg)
+
+	case restoredMsg:
+		return m.restored(msg)
+
+	case gitActionMsg:
+		if msg.err != nil {
+			m.setFlash(msg.err.Error(), true)
+		} else {
+			m.setFlash(msg.desc, false)
+		}
+		return m, tea.Batch(loadFiles(m.allFiles, m.base), m.reloadViewer())
+
 	case tea.KeyMsg:
+		// A pending confirmation swallows the next key
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+
 		// Quick-fix mode intercepts all keys
 		if m.quickFix {
 			return m.updateQuickFix(msg)
 		}
+		if m.blockEdit {
+			return m.updateBlockEdit(msg)
+		}
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		if m.currentView == grepView && m.grepping {
+			return m.updateGrep(msg)
+		}
+
+		// The commit composer is a text editor — no global keys
+		if m.currentView == commitView {
+			return m.updateCommit(msg)
+		}
 
 		if m.currentView == fileListView && m.list.FilterState() == list.Filtering {
 			var cmd tea.Cmd
 			m.list, cmd = m.list.Update(msg)
 			return m, cmd
 		}
+		if m.currentView == logView && m.logList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.logList, cmd = m.logList.Update(msg)
+			return m, cmd
+		}
+		if m.currentView == baseView && m.baseList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.baseList, cmd = m.baseList.Update(msg)
+			return m, cmd
+		}
+		if m.currentView == editsView && m.editList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.editList, cmd = m.editList.Update(msg)
+			return m, cmd
+		}
+		if m.currentView == grepView && m.grepList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.grepList, cmd = m.grepList.Update(msg)
+			return m, cmd
+		}
+		if m.currentView == timelineView && m.timelineList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.timelineList, cmd = m.timelineList.Update(msg)
+			return m, cmd
+		}
+		if m.currentView == restoreView && m.restoreList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.restoreList, cmd = m.restoreList.Update(msg)
+			return m, cmd
+		}
 
 		// Global keybindings
-		if mdl, cmd, handled := m.handleGlobalKey(msg.String()); handled {
+		if mdl, cmd, handled := m.handleGlobalKey(msg); handled {
+			if mdl.currentView != fileViewerView {
+				mdl.travel = nil
+			}
 			return mdl, cmd
 		}
 
@@ -431,6 +888,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateFileList(msg)
 		case fileViewerView:
 			return m.updateFileViewer(msg)
+		case logView:
+			return m.updateLog(msg)
+		case baseView:
+			return m.updateBasePicker(msg)
+		case editsView:
+			return m.updateEditHistory(msg)
+		case grepView:
+			return m.updateGrep(msg)
+		case timelineView:
+			return m.updateTimeline(msg)
+		case restoreView:
+			return m.updateRestore(msg)
 		}
 	}
 
@@ -440,30 +909,162 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.quickFixInput, cmd = m.quickFixInput.Update(msg)
 		return m, cmd
 	}
+	if m.blockEdit {
+		var cmd tea.Cmd
+		m.blockInput, cmd = m.blockInput.Update(msg)
+		return m, cmd
+	}
+	if m.searching {
+		var cmd tea.Cmd
+		m.searchInput, cmd = m.searchInput.Update(msg)
+		return m, cmd
+	}
+	if m.currentView == commitView {
+		var cmd tea.Cmd
+		m.commitInput, cmd = m.commitInput.Update(msg)
+		return m, cmd
+	}
 
 	if m.currentView == fileListView {
 		var cmd tea.Cmd
 		m.list, cmd = m.list.Update(msg)
 		return m, cmd
 	}
+	if m.currentView == logView {
+		var cmd tea.Cmd
+		m.logList, cmd = m.logList.Update(msg)
+		return m, cmd
+	}
+	if m.currentView == baseView {
+		var cmd tea.Cmd
+		m.baseList, cmd = m.baseList.Update(msg)
+		return m, cmd
+	}
+	if m.currentView == editsView {
+		var cmd tea.Cmd
+		m.editList, cmd = m.editList.Update(msg)
+		return m, cmd
+	}
+	if m.currentView == grepView && m.grepping {
+		var cmd tea.Cmd
+		m.grepInput, cmd = m.grepInput.Update(msg)
+		return m, cmd
+	}
+	if m.currentView == grepView {
+		var cmd tea.Cmd
+		m.grepList, cmd = m.grepList.Update(msg)
+		return m, cmd
+	}
+	if m.currentView == timelineView {
+		var cmd tea.Cmd
+		m.timelineList, cmd = m.timelineList.Update(msg)
+		return m, cmd
+	}
+	if m.currentView == restoreView {
+		var cmd tea.Cmd
+		m.restoreList, cmd = m.restoreList.Update(msg)
+		return m, cmd
+	}
 	return m, nil
 }
 
-func (m model) handleGlobalKey(key string) (model, tea.Cmd, bool) {
-	switch key {
-	case "?":
+// refreshCmds reloads the file list and, unless the user is editing, the open
+// file. Committed files never change, but the log can grow.
+func (m *model) refreshCmds() []tea.Cmd {
+	cmds := []tea.Cmd{loadFiles(m.allFiles, m.base)}
+	if m.currentView == logView {
+		cmds = append(cmds, loadLog())
+	}
+	if m.currentView == fileViewerView && m.currentFile != "" && !m.quickFix && !m.blockEdit && m.logCommit == nil && m.travel == nil {
+		m.loadSeq++
+		m.autoRefresh = true
+		item, ok := m.list.SelectedItem().(fileEntry)
+		status := ""
+		if ok && item.path == m.currentFile {
+			status = item.status
+		}
+		cmds = append(cmds, m.loadContent(m.currentFile, status))
+	}
+	return cmds
+}
+
+// returnViewerTo makes esc leave the viewer for v, with the diff toggle as
+// it was before the viewer was opened from there.
+func (m *model) returnViewerTo(v view) {
+	if m.viewerReturn == fileListView {
+		m.viewerDiff = m.diffMode
+	}
+	m.viewerReturn = v
+}
+
+// loadContent loads path into the viewer, from the commit being browsed or
+// from the worktree.
+func (m model) loadContent(path, status string) tea.Cmd {
+	innerW, _ := m.innerSize()
+	if m.travel != nil {
+		return loadTravelContent(*m.travel, path, m.diffMode, m.mdPreview, m.loadSeq, innerW)
+	}
+	if m.logCommit != nil {
+		return loadRevContent(m.logCommit.hash, path, m.diffMode, m.mdPreview, status, m.loadSeq, innerW)
+	}
+	return loadFileContent(path, m.diffMode, m.diffStaged, m.mdPreview, status, m.base, m.loadSeq, innerW)
+}
+
+// showStats reports whether the file list carries line counts: changed
+// files in the worktree, not all files or a commit's.
+func (m model) showStats() bool {
+	return !m.allFiles && m.logCommit == nil
+}
+
+// readOnly explains why the viewer's file can't be edited, or is "" when
+// it can.
+func (m model) readOnly() string {
+	switch {
+	case m.logCommit != nil:
+		return "committed files are read-only"
+	case m.travel != nil:
+		return "snapshots are read-only"
+	}
+	return ""
+}
+
+func (m model) handleGlobalKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Help):
 		m.showHelp = !m.showHelp
+		m.helpScroll = 0
 		return m, nil, true
+	case m.showHelp && key.Matches(msg, m.keys.Down):
+		m.helpScroll = min(m.helpScroll+1, m.helpMaxScroll())
+		return m, nil, true
+	case m.showHelp && key.Matches(msg, m.keys.Up):
+		m.helpScroll = max(m.helpScroll-1, 0)
+		return m, nil, true
+	case key.Matches(msg, m.keys.Undo):
+		mdl, cmd := m.undoEdit()
+		return mdl.(model), cmd, true
+	case key.Matches(msg, m.keys.Redo):
+		mdl, cmd := m.redoEdit()
+		return mdl.(model), cmd, true
+	case key.Matches(msg, m.keys.EditHistory) && m.currentView != editsView:
+		mdl, cmd := m.openEditHistory()
+		return mdl.(model), cmd, true
+	case key.Matches(msg, m.keys.Grep) && m.currentView != grepView:
+		mdl, cmd := m.openGrep()
+		return mdl.(model), cmd, true
+	case key.Matches(msg, m.keys.Timeline) && m.currentView != timelineView:
+		mdl, cmd := m.openTimeline()
+		return mdl.(model), cmd, true
 	}
 	return m, nil, false
 }
 
 func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q", "ctrl+c":
+	switch {
+	case key.Matches(msg, m.keys.Quit):
 		return m, tea.Quit
 
-	case "esc":
+	case key.Matches(msg, m.keys.Back):
 		if m.showHelp {
 			m.showHelp = false
 			return m, nil
@@ -476,6 +1077,9 @@ func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if m.logCommit != nil {
+			return m.closeRev()
+		}
 		// In tree mode, esc goes up to parent (same as left/h)
 		if m.treeMode && m.treeCwd != nil && m.treeCwd != m.treeRoot {
 			pp := parentPath(m.treeCwd.path)
@@ -496,7 +1100,7 @@ func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "enter", "right", "l":
+	case key.Matches(msg, m.keys.Open, m.keys.Right):
 		if m.treeMode {
 			if entry, ok := m.list.SelectedItem().(treeEntry); ok {
 				node := entry.node
@@ -520,17 +1124,18 @@ func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.currentFile = node.path
 				m.hScroll = 0
 				m.cursorLine = 0
+				m.diffStaged = false
 				m.mdPreview = isPreviewable(node.path)
 				m.loadSeq++
 				innerW, innerH := m.innerSize()
 				m.viewport = viewport.New(innerW-1, innerH-2)
 				m.viewport.SetContent("Loading...")
-				return m, loadFileContent(node.path, m.diffMode, m.mdPreview, node.status, m.loadSeq, innerW)
+				return m, m.loadContent(node.path, node.status)
 			}
 			return m, nil
 		}
 		// Non-tree: only enter opens files
-		if msg.String() != "enter" {
+		if !key.Matches(msg, m.keys.Open) {
 			break
 		}
 		item, ok := m.list.SelectedItem().(fileEntry)
@@ -541,13 +1146,15 @@ func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.hScroll = 0
 		m.cursorLine = 0
 		m.mdPreview = isPreviewable(item.path)
+		// Show the unstaged layer first unless everything is already staged
+		m.diffStaged = item.hasStaged() && !item.hasUnstaged()
 		m.loadSeq++
 		innerW, innerH := m.innerSize()
 		m.viewport = viewport.New(innerW-1, innerH-2)
 		m.viewport.SetContent("Loading...")
-		return m, loadFileContent(item.path, m.diffMode, m.mdPreview, item.status, m.loadSeq, innerW)
+		return m, m.loadContent(item.path, item.status)
 
-	case "left", "h":
+	case key.Matches(msg, m.keys.Left):
 		if m.treeMode && m.treeCwd != nil && m.treeCwd != m.treeRoot {
 			// Go up to parent directory
 			pp := parentPath(m.treeCwd.path)
@@ -568,7 +1175,21 @@ func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-	case "t":
+	case key.Matches(msg, m.keys.Restore):
+		switch e := m.list.SelectedItem().(type) {
+		case fileEntry:
+			return m.openRestore(e.path)
+		case treeEntry:
+			if !e.node.isDir {
+				return m.openRestore(e.node.path)
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Tree):
+		if m.logCommit != nil {
+			return m, nil
+		}
 		if !m.allFiles {
 			// Changed files (flat) → All files (tree)
 			m.allFiles = true
@@ -581,16 +1202,64 @@ func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.treeRoot = nil
 			m.treeCwd = nil
 		}
-		return m, loadFiles(m.allFiles)
+		// The other list isn't a change to anything
+		m.prevSnapshot = snapshot{}
+		return m, loadFiles(m.allFiles, m.base)
 
-	case "d":
+	case key.Matches(msg, m.keys.Diff):
 		m.diffMode = !m.diffMode
 		return m, nil
 
-	case "r":
-		return m, loadFiles(m.allFiles)
+	case key.Matches(msg, m.keys.SplitDiff):
+		m.splitDiff = !m.splitDiff
+		return m, nil
 
-	case "shift+down":
+	case key.Matches(msg, m.keys.Sort):
+		if m.treeMode {
+			m.setFlash("sorting works on the changed-file list", true)
+			return m, nil
+		}
+		m.fileSort = (m.fileSort + 1) % sortMode(len(sortModeNames))
+		m.setFileItems(listFiles(m.list.Items()))
+		m.setFlash("sorted by "+m.fileSort.String(), false)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Group):
+		if m.treeMode {
+			m.setFlash("grouping works on the changed-file list", true)
+			return m, nil
+		}
+		m.fileGroup = (m.fileGroup + 1) % groupMode(len(groupModeNames))
+		m.setFileItems(listFiles(m.list.Items()))
+		if m.fileGroup == groupNone {
+			m.setFlash("not grouped", false)
+		} else {
+			m.setFlash("grouped by "+m.fileGroup.String(), false)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Commit):
+		return m.openCommit()
+
+	case key.Matches(msg, m.keys.Log):
+		if m.logCommit != nil {
+			return m.closeRev()
+		}
+		return m.openLog()
+
+	case key.Matches(msg, m.keys.Base):
+		if m.logCommit != nil {
+			return m, nil
+		}
+		return m.openBasePicker()
+
+	case key.Matches(msg, m.keys.Refresh):
+		if m.logCommit != nil {
+			return m, loadRevFiles(*m.logCommit)
+		}
+		return m, loadFiles(m.allFiles, m.base)
+
+	case key.Matches(msg, m.keys.HalfPageDown):
 		_, innerH := m.innerSize()
 		half := innerH / 2
 		idx := m.list.Index() + half
@@ -598,9 +1267,10 @@ func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			idx = max
 		}
 		m.list.Select(idx)
+		skipSectionHeader(&m.list, true)
 		return m, nil
 
-	case "shift+up":
+	case key.Matches(msg, m.keys.HalfPageUp):
 		_, innerH := m.innerSize()
 		half := innerH / 2
 		idx := m.list.Index() - half
@@ -608,33 +1278,67 @@ func (m model) updateFileList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			idx = 0
 		}
 		m.list.Select(idx)
+		skipSectionHeader(&m.list, false)
 		return m, nil
 	}
 
 	// In tree mode, populate all files before entering filter so `/` searches everything
-	if m.treeMode && m.treeRoot != nil && msg.String() == "/" {
+	if m.treeMode && m.treeRoot != nil && key.Matches(msg, m.keys.Filter) {
 		m.list.SetItems(allFileItems(m.treeRoot))
 	}
 
 	var cmd tea.Cmd
+	prev := m.list.Index()
 	m.list, cmd = m.list.Update(msg)
+	skipSectionHeader(&m.list, m.list.Index() >= prev)
 	return m, cmd
 }
 
 func (m model) updateFileViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q", "ctrl+c":
+	switch {
+	case key.Matches(msg, m.keys.Quit):
 		return m, tea.Quit
 
-	case "esc":
+	case key.Matches(msg, m.keys.Back):
 		if m.showHelp {
 			m.showHelp = false
 			return m, nil
 		}
-		m.currentView = fileListView
+		if m.visual {
+			m.visual = false
+			yoff := m.viewport.YOffset
+			m.viewport.SetContent(m.viewportContent())
+			m.viewport.SetYOffset(yoff)
+			return m, nil
+		}
+		if m.search != nil {
+			m.clearSearch()
+			return m, nil
+		}
+		if m.viewerReturn != fileListView {
+			m.diffMode = m.viewerDiff
+		}
+		m.currentView = m.viewerReturn
+		m.viewerReturn = fileListView
+		m.travel = nil
 		return m, nil
 
-	case "d":
+	case key.Matches(msg, m.keys.Filter):
+		return m.openSearch()
+
+	case key.Matches(msg, m.keys.SearchNext):
+		if m.search != nil {
+			m.jumpToMatch(true)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.SearchPrev):
+		if m.search != nil {
+			m.jumpToMatch(false)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Diff):
 		if m.mdPreview {
 			return m, nil
 		}
@@ -646,10 +1350,22 @@ func (m model) updateFileViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if ok {
 			status = item.status
 		}
-		innerW, _ := m.innerSize()
-		return m, loadFileContent(m.currentFile, m.diffMode, m.mdPreview, status, m.loadSeq, innerW)
+		return m, m.loadContent(m.currentFile, status)
 
-	case "p":
+	case key.Matches(msg, m.keys.SplitDiff):
+		wasSplit := m.splitActive()
+		m.splitDiff = !m.splitDiff
+		if m.splitDiff && m.diffMode && !m.splitActive() {
+			m.setFlash("too narrow for a side-by-side diff", true)
+		}
+		m.remapCursor(wasSplit)
+		m.viewport.SetContent(m.viewportContent())
+		if m.cursorLine < m.viewport.YOffset || m.cursorLine >= m.viewport.YOffset+m.viewport.Height {
+			m.viewport.SetYOffset(m.cursorLine - m.viewport.Height/2)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Preview):
 		if isPreviewable(m.currentFile) {
 			m.mdPreview = !m.mdPreview
 			if m.mdPreview {
@@ -662,27 +1378,31 @@ func (m model) updateFileViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if ok {
 				status = item.status
 			}
-			innerW, _ := m.innerSize()
-			return m, loadFileContent(m.currentFile, m.diffMode, m.mdPreview, status, m.loadSeq, innerW)
+			return m, m.loadContent(m.currentFile, status)
 		}
 		return m, nil
 
-	case "e":
+	case key.Matches(msg, m.keys.QuickFix):
 		// Quick-fix: no-op in markdown preview
 		if m.mdPreview {
 			return m, nil
 		}
+		if reason := m.readOnly(); reason != "" {
+			m.setFlash(reason, true)
+			return m, nil
+		}
 		// Determine the real file line to edit
 		fileLine := m.cursorLine
 		if m.diffMode {
 			// Map diff cursor line to actual file line number
 			diffLines := strings.Split(m.rawContent, "\n")
 			labels := diffLineNumbers(diffLines)
-			if m.cursorLine >= len(labels) || labels[m.cursorLine] == "" || labels[m.cursorLine] == "~" {
+			dl := m.diffLine(m.cursorLine)
+			if dl >= len(labels) || labels[dl] == "" || labels[dl] == "~" {
 				return m, nil // header, deleted line, or hunk marker — can't edit
 			}
 			var n int
-			if _, err := fmt.Sscanf(labels[m.cursorLine], "%d", &n); err != nil {
+			if _, err := fmt.Sscanf(labels[dl], "%d", &n); err != nil {
 				return m, nil
 			}
 			fileLine = n - 1 // labels are 1-based
@@ -706,17 +1426,74 @@ func (m model) updateFileViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		ti.Width = innerW - 8 // leave room for gutter
 		ti.Focus()
 		m.quickFix = true
+		m.fixBase = newEditBase(content, fileLine, []string{strings.TrimRight(lines[fileLine], "\r")})
 		m.quickFixLine = fileLine
 		m.quickFixCursorLine = m.cursorLine
 		m.quickFixInput = ti
 		return m, textinput.Blink
 
-	case "j", "down":
+	case key.Matches(msg, m.keys.BlockEdit):
+		return m.openBlockEdit()
+
+	case key.Matches(msg, m.keys.OpenEditor):
+		return m.openInEditor()
+
+	case key.Matches(msg, m.keys.Blame):
+		return m.toggleBlame()
+
+	case key.Matches(msg, m.keys.Restore):
+		if m.travel != nil && !m.travel.toWorktree && m.travel.to != "" {
+			// Put back the state on screen
+			m.confirmRestore(m.currentFile, restoreTarget{name: m.travel.at.Format("15:04:05"), obj: m.travel.to, at: m.travel.at})
+			return m, nil
+		}
+		return m.openRestore(m.currentFile)
+
+	case key.Matches(msg, m.keys.Commit):
+		return m.openCommit()
+
+	case key.Matches(msg, m.keys.ToggleStaged):
+		if !m.diffMode || m.logCommit != nil || m.travel != nil || m.base != "" {
+			return m, nil
+		}
+		m.diffStaged = !m.diffStaged
+		m.hScroll = 0
+		m.cursorLine = 0
+		m.loadSeq++
+		item, ok := m.list.SelectedItem().(fileEntry)
+		status := ""
+		if ok {
+			status = item.status
+		}
+		return m, m.loadContent(m.currentFile, status)
+
+	case key.Matches(msg, m.keys.SelectLines):
+		// Selections feed staging in diff mode and the block editor anywhere
+		if m.mdPreview || m.logCommit != nil || m.travel != nil {
+			return m, nil
+		}
+		m.visual = !m.visual
+		m.visualAnchor = m.cursorLine
+		yoff := m.viewport.YOffset
+		m.viewport.SetContent(m.viewportContent())
+		m.viewport.SetYOffset(yoff)
+		return m, nil
+
+	case key.Matches(msg, m.keys.StageHunk):
+		return m.applyHunk(hunkStage)
+
+	case key.Matches(msg, m.keys.UnstageHunk):
+		return m.applyHunk(hunkUnstage)
+
+	case key.Matches(msg, m.keys.DiscardHunk):
+		return m.applyHunk(hunkDiscard)
+
+	case key.Matches(msg, m.keys.Down):
 		if m.mdPreview {
 			m.viewport.LineDown(1)
 			return m, nil
 		}
-		totalLines := strings.Count(m.rawContent, "\n") + 1
+		totalLines := m.lineCount()
 		if m.cursorLine < totalLines-1 {
 			m.cursorLine++
 		}
@@ -724,13 +1501,12 @@ func (m model) updateFileViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.cursorLine >= m.viewport.YOffset+m.viewport.Height {
 			m.viewport.SetYOffset(m.cursorLine - m.viewport.Height + 1)
 		}
-		innerW, _ := m.innerSize()
 		yoff := m.viewport.YOffset
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(yoff)
 		return m, nil
 
-	case "k", "up":
+	case key.Matches(msg, m.keys.Up):
 		if m.mdPreview {
 			m.viewport.LineUp(1)
 			return m, nil
@@ -741,19 +1517,18 @@ func (m model) updateFileViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.cursorLine < m.viewport.YOffset {
 			m.viewport.SetYOffset(m.cursorLine)
 		}
-		innerW, _ := m.innerSize()
 		yoff := m.viewport.YOffset
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(yoff)
 		return m, nil
 
-	case "shift+down":
+	case key.Matches(msg, m.keys.HalfPageDown):
 		if m.mdPreview {
 			m.viewport.HalfViewDown()
 			return m, nil
 		}
 		half := m.viewport.Height / 2
-		totalLines := strings.Count(m.rawContent, "\n") + 1
+		totalLines := m.lineCount()
 		m.cursorLine += half
 		if m.cursorLine >= totalLines {
 			m.cursorLine = totalLines - 1
@@ -761,13 +1536,12 @@ func (m model) updateFileViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.cursorLine >= m.viewport.YOffset+m.viewport.Height {
 			m.viewport.SetYOffset(m.cursorLine - m.viewport.Height + 1)
 		}
-		innerW, _ := m.innerSize()
 		yoff := m.viewport.YOffset
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(yoff)
 		return m, nil
 
-	case "shift+up":
+	case key.Matches(msg, m.keys.HalfPageUp):
 		if m.mdPreview {
 			m.viewport.HalfViewUp()
 			return m, nil
@@ -780,54 +1554,49 @@ func (m model) updateFileViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.cursorLine < m.viewport.YOffset {
 			m.viewport.SetYOffset(m.cursorLine)
 		}
-		innerW, _ := m.innerSize()
 		yoff := m.viewport.YOffset
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(yoff)
 		return m, nil
 
-	case "g":
+	case key.Matches(msg, m.keys.Top):
 		if m.mdPreview {
 			m.viewport.GotoTop()
 			return m, nil
 		}
 		m.cursorLine = 0
 		m.viewport.GotoTop()
-		innerW, _ := m.innerSize()
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(0)
 		return m, nil
 
-	case "G":
+	case key.Matches(msg, m.keys.Bottom):
 		if m.mdPreview {
 			m.viewport.GotoBottom()
 			return m, nil
 		}
-		totalLines := strings.Count(m.rawContent, "\n") + 1
+		totalLines := m.lineCount()
 		m.cursorLine = totalLines - 1
 		m.viewport.GotoBottom()
-		innerW, _ := m.innerSize()
 		yoff := m.viewport.YOffset
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(yoff)
 		return m, nil
 
-	case "h", "left":
+	case key.Matches(msg, m.keys.Left):
 		m.hScroll -= 4
 		if m.hScroll < 0 {
 			m.hScroll = 0
 		}
-		innerW, _ := m.innerSize()
 		yoff := m.viewport.YOffset
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(yoff)
 		return m, nil
 
-	case "l", "right":
+	case key.Matches(msg, m.keys.Right):
 		m.hScroll += 4
-		innerW, _ := m.innerSize()
 		yoff := m.viewport.YOffset
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(yoff)
 		return m, nil
 	}
@@ -852,14 +1621,12 @@ func (m model) updateQuickFix(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if ok {
 			status = item.status
 		}
-		innerW, _ := m.innerSize()
-		return m, writeAndReloadCmd(m.currentFile, m.quickFixLine, newContent, m.diffMode, m.mdPreview, status, m.loadSeq, innerW)
+		return m, writeAndReloadCmd(m.currentFile, &m.fixBase, m.quickFixLine, m.quickFixLine, []string{newContent}, m.loadContent(m.currentFile, status))
 	case tea.KeyEsc:
 		m.quickFix = false
 		// Re-render to remove text input overlay
-		innerW, _ := m.innerSize()
 		yoff := m.viewport.YOffset
-		m.viewport.SetContent(applyHScroll(m.rawContent, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, m.cursorLine))
+		m.viewport.SetContent(m.viewportContent())
 		m.viewport.SetYOffset(yoff)
 		return m, nil
 	default:
@@ -869,16 +1636,6 @@ func (m model) updateQuickFix(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
-// writeAndReloadCmd writes the edited line then immediately reloads the file content.
-// This avoids a race where loadFileContent reads before the write finishes.
-func writeAndReloadCmd(path string, lineNum int, newContent string, diffMode, mdPreview bool, status string, seq, width int) tea.Cmd {
-	return func() tea.Msg {
-		_ = writeFileLine(path, lineNum, newContent)
-		// Now load inline — reuse the same logic as loadFileContent
-		return loadFileContent(path, diffMode, mdPreview, status, seq, width)()
-	}
-}
-
 // addScrollbar overlays a scrollbar column on the right edge of content.
 func addScrollbar(content string, contentWidth, height, totalItems, visibleItems, offset int) string {
 	if totalItems <= visibleItems {
@@ -932,15 +1689,21 @@ func addScrollbar(content string, contentWidth, height, totalItems, visibleItems
 
 // renderFileList renders the file list with a scrollbar overlay.
 func (m model) renderFileList() string {
-	listView := m.list.View()
-	innerW, innerH := m.innerSize()
-	total := len(m.list.VisibleItems())
+	return m.renderList(m.list)
+}
+
+// renderList renders l with a scrollbar overlay.
+func (m model) renderList(l list.Model) string {
+	listView := l.View()
+	innerW, _ := m.innerSize()
+	innerH := l.Height()
+	total := len(l.VisibleItems())
 	visible := innerH // list visible area height
 	if visible > total {
 		visible = total
 	}
 	// Compute scroll offset from current index
-	idx := m.list.Index()
+	idx := l.Index()
 	offset := 0
 	if total > innerH {
 		// The list keeps the cursor visible, so offset ≈ idx - half visible
@@ -955,10 +1718,29 @@ func (m model) renderFileList() string {
 	return addScrollbar(listView, innerW-1, innerH, total, innerH, offset)
 }
 
+// viewportContent renders rawContent at the current scroll offset, cursor and selection.
+func (m model) viewportContent() string {
+	innerW, _ := m.innerSize()
+	content := m.rawContent
+	if re := m.activeSearch(); re != nil {
+		content = highlightMatches(content, re)
+	}
+	if m.splitActive() {
+		return renderSplit(content, m.hScroll, innerW-1, m.cursorLine, m.selection())
+	}
+	var blame []string
+	if m.blameShown() && m.blameFor == m.currentFile && m.blameLines != nil {
+		blame = blameGutter(m.blameLines, time.Now())
+	}
+	return applyHScroll(content, m.hScroll, innerW-1, m.diffMode, m.mdPreview, m.changedLines, blame, m.cursorLine, m.selection())
+}
+
 // applyHScroll shifts each line of content horizontally using ANSI-aware truncation.
 // Line numbers are always shown. In diff mode, numbers reflect actual file lines
 // (deletions get no number, additions and context lines track the new-file position).
-func applyHScroll(content string, offset, width int, diffMode, hideLineNums bool, changedLines map[int]bool, cursorLine int) string {
+// Lines inside sel get a selection bar in the gutter. A non-nil blame column
+// is drawn left of the numbers.
+func applyHScroll(content string, offset, width int, diffMode, hideLineNums bool, changedLines map[int]bool, blame []string, cursorLine int, sel lineRange) string {
 	// Replace tabs with spaces so width counting matches terminal rendering.
 	content = strings.ReplaceAll(content, "\t", "    ")
 
@@ -997,6 +1779,11 @@ func applyHScroll(content string, offset, width int, diffMode, hideLineNums bool
 	}
 	numStyle := lineNumStyle.Width(maxLabel)
 	gutterW := maxLabel + 1 + 1 // digits + bar + space
+	blameW := 0
+	if len(blame) > 0 {
+		blameW = lipgloss.Width(blame[0])
+		gutterW += blameW
+	}
 
 	contentW := width - gutterW
 	if contentW < 10 {
@@ -1032,10 +1819,24 @@ func applyHScroll(content string, offset, width int, diffMode, hideLineNums bool
 		if i == cursorLine {
 			barStyle = cursorBarStyle
 			cursorNumStyle = cursorNumHighlightStyle.Width(maxLabel)
+		} else if sel.contains(i) {
+			barStyle = selectionBarStyle
+			cursorNumStyle = cursorNumHighlightStyle.Width(maxLabel)
 		} else if !diffMode && changedLines[i+1] {
 			barStyle = dirtyIndicatorStyle
 		}
-		line = cursorNumStyle.Render(lineLabels[i]) + barStyle.Render("│") + " " + line
+		bar := "│"
+		if sel.contains(i) {
+			bar = "┃"
+		}
+		line = cursorNumStyle.Render(lineLabels[i]) + barStyle.Render(bar) + " " + line
+		if blameW > 0 {
+			if i < len(blame) {
+				line = blame[i] + line
+			} else {
+				line = strings.Repeat(" ", blameW) + line
+			}
+		}
 		// Safety: ensure final composed line fits within width
 		if width > 0 {
 			line = ansi.Truncate(line, width, "")
@@ -1193,7 +1994,8 @@ func (m model) View() string {
 	if m.treeMode {
 		m.list.SetDelegate(treeDelegate{recentFiles: m.recentFiles})
 	} else {
-		m.list.SetDelegate(fileDelegate{recentFiles: m.recentFiles})
+		_, most := listStats(m.list.Items())
+		m.list.SetDelegate(fileDelegate{recentFiles: m.recentFiles, stats: m.showStats(), mostChurn: most})
 	}
 
 	header := m.renderHeader()
@@ -1212,14 +2014,71 @@ func (m model) renderCmdBar() string {
 	type hint struct{ key, desc string }
 
 	var hints []hint
-	if m.quickFix && m.currentView == fileViewerView {
+	if m.confirm != nil {
+		hints = []hint{
+			{"y", m.confirm.text},
+			{"n", "cancel"},
+		}
+	} else if m.currentView == commitView {
+		hints = []hint{
+			{firstKey(m.keys.CommitSubmit), "commit"},
+			{firstKey(m.keys.CommitAmend), "amend"},
+			{firstKey(m.keys.CommitSignoff), "signoff"},
+			{firstKey(m.keys.Back), "cancel"},
+		}
+	} else if m.currentView == editsView {
+		hints = []hint{
+			{firstKey(m.keys.Open), "open"},
+			{firstKey(m.keys.Undo), "undo"},
+			{firstKey(m.keys.Redo), "redo"},
+			{firstKey(m.keys.Back), "close"},
+		}
+	} else if m.currentView == grepView && !m.grepping {
+		hints = []hint{
+			{firstKey(m.keys.Open), "open"},
+			{firstKey(m.keys.Grep), "new search"},
+			{firstKey(m.keys.GrepUntracked), "untracked"},
+			{firstKey(m.keys.Back), "close"},
+		}
+	} else if m.currentView == timelineView {
+		hints = []hint{
+			{firstKey(m.keys.Open), "show change"},
+			{firstKey(m.keys.Diff), "diff to now"},
+			{firstKey(m.keys.Restore), "restore"},
+			{firstKey(m.keys.Filter), "filter"},
+			{firstKey(m.keys.Back), "close"},
+		}
+	} else if m.currentView == restoreView {
+		hints = []hint{
+			{firstKey(m.keys.Open), "restore"},
+			{firstKey(m.keys.Back), "cancel"},
+		}
+	} else if m.currentView == baseView {
+		hints = []hint{
+			{firstKey(m.keys.Open), "diff against ref"},
+			{firstKey(m.keys.MergeBase), "against merge-base"},
+			{firstKey(m.keys.Back), "cancel"},
+		}
+	} else if m.blockEdit && m.currentView == fileViewerView {
+		hints = []hint{
+			{firstKey(m.keys.EditSave), "save"},
+			{firstKey(m.keys.EditInsertLine), "new line"},
+			{firstKey(m.keys.EditDeleteLine), "delete line"},
+			{firstKey(m.keys.Back), "cancel"},
+		}
+	} else if m.quickFix && m.currentView == fileViewerView {
 		hints = []hint{
 			{"enter", "save"},
 			{"esc", "cancel"},
 		}
+	} else if m.searching || (m.grepping && m.currentView == grepView) {
+		hints = []hint{
+			{"enter", "search"},
+			{"esc", "cancel"},
+		}
 	} else {
 		hints = []hint{
-			{"?", "help"},
+			{firstKey(m.keys.Help), "help"},
 		}
 	}
 
@@ -1231,19 +2090,74 @@ func (m model) renderCmdBar() string {
 
 	bar := strings.Join(parts, cmdSepStyle.Render("  "))
 
-	// Position counter for file list view
+	// Position counter for file list view; a fresh status message takes its place
 	var posCounter string
-	if m.currentView == fileListView {
-		total := len(m.list.VisibleItems())
+	if m.flash != "" && time.Now().Before(m.flashUntil) {
+		if m.flashErr {
+			posCounter = flashErrStyle.Render(m.flash)
+		} else {
+			posCounter = flashStyle.Render(m.flash)
+		}
+	} else if m.currentView == fileListView {
+		items := m.list.VisibleItems()
+		total, pos := len(items), m.list.Index()+1
+		if !m.treeMode {
+			// Count files only, not section headers
+			total = len(listFiles(items))
+			pos = len(listFiles(items[:min(pos, len(items))]))
+		}
+		if total > 0 {
+			posCounter = cmdDescStyle.Render(fmt.Sprintf("%d/%d", pos, total))
+		}
+	} else if m.currentView == logView {
+		total := len(m.logList.VisibleItems())
 		if total > 0 {
-			posCounter = cmdDescStyle.Render(fmt.Sprintf("%d/%d", m.list.Index()+1, total))
+			posCounter = cmdDescStyle.Render(fmt.Sprintf("%d/%d", m.logList.Index()+1, total))
+		}
+	} else if m.currentView == fileViewerView && m.searching {
+		if _, err := compileSearch(m.searchInput.Value()); err != nil {
+			posCounter = flashErrStyle.Render("invalid pattern")
+		} else if m.searchInput.Value() != "" {
+			n := len(m.searchRows())
+			noun := "matches"
+			if n == 1 {
+				noun = "match"
+			}
+			posCounter = cmdDescStyle.Render(fmt.Sprintf("%d %s", n, noun))
+		}
+	} else if m.currentView == fileViewerView && m.search != nil {
+		posCounter = cmdDescStyle.Render(m.searchCounter())
+	} else if m.currentView == fileViewerView && m.blameShown() && m.blameCounter() != "" {
+		posCounter = cmdDescStyle.Render(ansi.Truncate(m.blameCounter(), max(m.width/2, 20), "…"))
+	} else if m.currentView == grepView && !m.grepping {
+		if total := len(m.grepList.VisibleItems()); total > 0 {
+			posCounter = cmdDescStyle.Render(fmt.Sprintf("%d/%d", m.grepList.Index()+1, total))
+		}
+	} else if m.currentView == timelineView {
+		if total := len(m.timelineList.VisibleItems()); total > 0 {
+			posCounter = cmdDescStyle.Render(fmt.Sprintf("%d/%d", m.timelineList.Index()+1, total))
+		} else {
+			posCounter = cmdDescStyle.Render("no changes yet")
+		}
+	} else if m.currentView == editsView {
+		if total := len(m.editList.VisibleItems()); total > 0 {
+			posCounter = cmdDescStyle.Render(fmt.Sprintf("%d/%d", m.editList.Index()+1, total))
+		} else {
+			posCounter = cmdDescStyle.Render("no edits yet")
 		}
 	}
 
 	left := "  " + bar
+	if m.searching {
+		left = "  " + m.searchInput.View() + "  " + bar
+	}
 	if posCounter != "" {
-		leftW := lipgloss.Width(left)
 		counterW := lipgloss.Width(posCounter)
+		// Hints give way to the counter or message rather than wrapping
+		if max := m.width - counterW - 2; lipgloss.Width(left) > max {
+			left = ansi.Truncate(left, max, "…")
+		}
+		leftW := lipgloss.Width(left)
 		gap := m.width - leftW - counterW - 1
 		if gap < 1 {
 			gap = 1
@@ -1257,7 +2171,7 @@ func (m model) renderCmdBar() string {
 
 // renderPanel wraps the main content in a rounded border.
 func (m model) renderPanel() string {
-	focused := m.currentView == fileViewerView
+	focused := m.currentView != fileListView && m.currentView != logView && m.currentView != baseView && m.currentView != editsView && m.currentView != grepView && m.currentView != timelineView && m.currentView != restoreView
 	innerW, innerH := m.innerSize()
 
 	var content string
@@ -1266,6 +2180,21 @@ func (m model) renderPanel() string {
 		content = m.renderFileList()
 	case fileViewerView:
 		content = m.renderFileViewer(innerW)
+	case commitView:
+		content = m.renderCommit(innerW, innerH)
+	case logView:
+		content = m.renderList(m.logList)
+	case baseView:
+		m.baseList.SetDelegate(refDelegate{current: m.base})
+		content = m.renderList(m.baseList)
+	case editsView:
+		content = m.renderList(m.editList)
+	case grepView:
+		content = m.renderGrep()
+	case timelineView:
+		content = m.renderList(m.timelineList)
+	case restoreView:
+		content = m.renderRestore()
 	}
 
 	border := panelBorder(focused, innerW, innerH)
@@ -1293,17 +2222,33 @@ func (m model) renderFileViewer(width int) string {
 	breadcrumb := strings.Join(crumbs, breadcrumbSepStyle.Render(" / "))
 
 	// Status badge if available
-	if item, ok := m.list.SelectedItem().(fileEntry); ok {
+	if item, ok := m.list.SelectedItem().(fileEntry); ok && item.path == m.currentFile && m.travel == nil {
 		breadcrumb += " " + statusBadgeStyle(item.status).Render(statusLabel(item.status))
 	}
 
+	if m.logCommit != nil {
+		breadcrumb += " " + historyBadgeStyle.Render(m.logCommit.short)
+	}
+	if m.travel != nil {
+		breadcrumb += " " + historyBadgeStyle.Render(m.travel.label())
+	}
 	if m.diffMode {
 		breadcrumb += " " + diffBadgeStyle.Render("DIFF")
+		// A commit, a snapshot or a base comparison has a single layer
+		switch {
+		case m.logCommit != nil, m.travel != nil:
+		case m.base != "":
+			breadcrumb += " " + baseBadgeStyle.Render("vs "+m.base)
+		case m.diffStaged:
+			breadcrumb += " " + stagedBadgeStyle.Render("STAGED")
+		default:
+			breadcrumb += " " + unstagedBadgeStyle.Render("UNSTAGED")
+		}
 	}
 	if m.mdPreview {
 		breadcrumb += " " + previewBadgeStyle.Render("PREVIEW")
 	}
-	if m.quickFix {
+	if m.quickFix || m.blockEdit {
 		breadcrumb += " " + fixBadgeStyle.Render("FIX")
 	}
 
@@ -1355,8 +2300,8 @@ func (m model) renderFileViewer(width int) string {
 			prefix := ""
 			if m.diffMode {
 				rawLines := strings.Split(m.rawContent, "\n")
-				if m.quickFixCursorLine < len(rawLines) {
-					stripped := ansi.Strip(rawLines[m.quickFixCursorLine])
+				if dl := m.diffLine(m.quickFixCursorLine); dl < len(rawLines) {
+					stripped := ansi.Strip(rawLines[dl])
 					if len(stripped) > 0 {
 						prefix = string(stripped[0]) // "+" or " "
 					}
@@ -1366,6 +2311,9 @@ func (m model) renderFileViewer(width int) string {
 		}
 		viewContent = strings.Join(vcLines, "\n")
 	}
+	if m.blockEdit {
+		viewContent = m.overlayBlockEditor(viewContent)
+	}
 
 	return header + "\n" + sep + "\n" + viewContent
 }
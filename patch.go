@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffHunk is a single @@ section of a unified diff.
+type diffHunk struct {
+	header string   // "@@ -a,b +c,d @@ ..." line
+	lines  []string // body lines, each with its ' ', '+', '-' or '\' prefix
+	start  int      // index of the header line within the full diff
+}
+
+// end returns the index one past the hunk's last body line.
+func (h diffHunk) end() int {
+	return h.start + 1 + len(h.lines)
+}
+
+// parsedDiff is a single-file unified diff split into its file header and hunks.
+type parsedDiff struct {
+	header []string // diff --git, index, ---, +++ lines
+	hunks  []diffHunk
+}
+
+// parseDiff splits a single-file unified diff into header and hunks.
+// Line indexes match strings.Split(diff, "\n"), which is also how
+// highlightDiff lays out the viewer, so cursor lines map directly.
+func parseDiff(diff string) parsedDiff {
+	var d parsedDiff
+	for i, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			d.hunks = append(d.hunks, diffHunk{header: line, start: i})
+			continue
+		}
+		if len(d.hunks) == 0 {
+			d.header = append(d.header, line)
+			continue
+		}
+		if line == "" {
+			// Trailing newline of the diff output
+			continue
+		}
+		h := &d.hunks[len(d.hunks)-1]
+		h.lines = append(h.lines, line)
+	}
+	return d
+}
+
+// hunkAt returns the index of the hunk containing diff line idx, or -1.
+func (d parsedDiff) hunkAt(idx int) int {
+	for i, h := range d.hunks {
+		if idx >= h.start && idx < h.end() {
+			return i
+		}
+	}
+	return -1
+}
+
+// hunkPatch builds a patch containing only hunk i, suitable for git apply.
+func (d parsedDiff) hunkPatch(i int) string {
+	h := d.hunks[i]
+	var b strings.Builder
+	for _, line := range d.header {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(h.header + "\n")
+	for _, line := range h.lines {
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// lineRange is an inclusive range of diff line indexes. The zero value is
+// not empty (it covers line 0), so use noRange for "no selection".
+type lineRange struct {
+	from, to int
+}
+
+var noRange = lineRange{from: 0, to: -1}
+
+func (r lineRange) contains(i int) bool {
+	return i >= r.from && i <= r.to
+}
+
+// rangePatch builds a patch that carries only the +/- lines inside sel.
+// Unselected changes are neutralised so the patch still applies cleanly:
+// when staging (reverse=false) unselected '-' lines become context and
+// unselected '+' lines are dropped; when the patch will be applied with
+// --reverse (unstage/discard) it is the other way round. Hunk headers are
+// recomputed. ok is false when sel contains no changed lines.
+func (d parsedDiff) rangePatch(sel lineRange, reverse bool) (patch string, ok bool) {
+	var b strings.Builder
+	for _, line := range d.header {
+		b.WriteString(line + "\n")
+	}
+
+	offset := 0 // cumulative new-old line delta of hunks written so far
+	for _, h := range d.hunks {
+		var body []string
+		oldCount, newCount := 0, 0
+		selected := false
+		kept := false // whether the previous source line was written
+		for j, line := range h.lines {
+			idx := h.start + 1 + j
+			in := sel.contains(idx)
+			switch {
+			case line == "" || line[0] == ' ':
+				body = append(body, line)
+				oldCount++
+				newCount++
+				kept = true
+			case line[0] == '\\':
+				// "\ No newline at end of file" belongs to the line before it
+				if kept {
+					body = append(body, line)
+				}
+			case line[0] == '+':
+				switch {
+				case in:
+					body = append(body, line)
+					newCount++
+					selected = true
+					kept = true
+				case reverse:
+					body = append(body, " "+line[1:])
+					oldCount++
+					newCount++
+					kept = true
+				default:
+					kept = false
+				}
+			case line[0] == '-':
+				switch {
+				case in:
+					body = append(body, line)
+					oldCount++
+					selected = true
+					kept = true
+				case !reverse:
+					body = append(body, " "+line[1:])
+					oldCount++
+					newCount++
+					kept = true
+				default:
+					kept = false
+				}
+			}
+		}
+		if !selected {
+			continue
+		}
+
+		oldStart, newStart := parseHunkStarts(h.header)
+		if reverse {
+			oldStart = newStart - offset
+		} else {
+			newStart = oldStart + offset
+		}
+		offset += newCount - oldCount
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, line := range body {
+			b.WriteString(line + "\n")
+		}
+		ok = true
+	}
+	return b.String(), ok
+}
+
+// parseHunkStarts extracts the old- and new-file start lines from a hunk header.
+// e.g. "@@ -10,5 +20,7 @@" → 10, 20
+func parseHunkStarts(header string) (oldStart, newStart int) {
+	oldStart, newStart = 1, parseHunkNewStart(header)
+	if minusIdx := strings.Index(header, "-"); minusIdx >= 0 {
+		fmt.Sscanf(header[minusIdx+1:], "%d", &oldStart)
+	}
+	return oldStart, newStart
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const twoHunkDiff = `diff --git a/f.txt b/f.txt
+index 2f0b8ee..6591d2b 100644
+--- a/f.txt
++++ b/f.txt
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,5 +8,5 @@ g
+ h
+ i
+ j
+-k
++K
+ l
+`
+
+func TestParseDiffHunks(t *testing.T) {
+	d := parseDiff(twoHunkDiff)
+	if len(d.header) != 4 {
+		t.Fatalf("header lines = %d, want 4", len(d.header))
+	}
+	if len(d.hunks) != 2 {
+		t.Fatalf("hunks = %d, want 2", len(d.hunks))
+	}
+	if d.hunks[0].start != 4 || d.hunks[1].start != 11 {
+		t.Errorf("hunk starts = %d, %d, want 4, 11", d.hunks[0].start, d.hunks[1].start)
+	}
+
+	cases := map[int]int{0: -1, 3: -1, 4: 0, 6: 0, 10: 0, 11: 1, 17: 1, 18: -1}
+	for line, want := range cases {
+		if got := d.hunkAt(line); got != want {
+			t.Errorf("hunkAt(%d) = %d, want %d", line, got, want)
+		}
+	}
+}
+
+func TestHunkPatch(t *testing.T) {
+	d := parseDiff(twoHunkDiff)
+	patch := d.hunkPatch(1)
+
+	if !strings.HasPrefix(patch, "diff --git a/f.txt b/f.txt\n") {
+		t.Errorf("patch missing file header:\n%s", patch)
+	}
+	if strings.Contains(patch, "+B") {
+		t.Errorf("patch leaked lines from the other hunk:\n%s", patch)
+	}
+	if !strings.Contains(patch, "@@ -8,5 +8,5 @@ g\n h\n i\n j\n-k\n+K\n l\n") {
+		t.Errorf("patch missing hunk body:\n%s", patch)
+	}
+}
+
+func TestRangePatchStage(t *testing.T) {
+	d := parseDiff(twoHunkDiff)
+	// Select only "+K" (line 16): "-k" must turn into context and the first hunk must vanish
+	patch, ok := d.rangePatch(lineRange{from: 16, to: 16}, false)
+	if !ok {
+		t.Fatal("expected a patch")
+	}
+	if strings.Contains(patch, "+B") || strings.Contains(patch, "-b") {
+		t.Errorf("unselected hunk leaked into patch:\n%s", patch)
+	}
+	if !strings.Contains(patch, "@@ -8,5 +8,6 @@\n h\n i\n j\n k\n+K\n l\n") {
+		t.Errorf("unexpected hunk:\n%s", patch)
+	}
+}
+
+func TestRangePatchReverse(t *testing.T) {
+	d := parseDiff(twoHunkDiff)
+	// Reverse-apply only "-b" (line 6): "+B" stays as context, so b is re-added before B
+	patch, ok := d.rangePatch(lineRange{from: 6, to: 6}, true)
+	if !ok {
+		t.Fatal("expected a patch")
+	}
+	if !strings.Contains(patch, "@@ -1,6 +1,5 @@\n a\n-b\n B\n c\n") {
+		t.Errorf("unexpected hunk:\n%s", patch)
+	}
+}
+
+func TestRangePatchNoChanges(t *testing.T) {
+	d := parseDiff(twoHunkDiff)
+	// Context lines only
+	if _, ok := d.rangePatch(lineRange{from: 8, to: 10}, false); ok {
+		t.Error("selection of context lines should not produce a patch")
+	}
+	if _, ok := d.rangePatch(noRange, false); ok {
+		t.Error("empty selection should not produce a patch")
+	}
+}
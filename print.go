@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	xansi "github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+)
+
+// printOptions control --print, which writes what the viewer would show to
+// stdout and exits instead of starting the TUI.
+type printOptions struct {
+	file  string // repo-relative file to print; "" for every changed file
+	diff  bool   // diffs instead of the file list or file content
+	base  string
+	width int // wrap width for markdown
+	color bool
+}
+
+// printColor decides whether --print output keeps its colors: mode is the
+// --color flag, and "auto" means only on a terminal and without NO_COLOR.
+func printColor(mode string, isTerminal bool) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto", "":
+		// NO_COLOR only counts when set to something
+		return isTerminal && os.Getenv("NO_COLOR") == "", nil
+	default:
+		return false, fmt.Errorf("--color must be auto, always or never, not %q", mode)
+	}
+}
+
+// runPrint renders opts and writes them to stdout.
+func runPrint(opts printOptions) error {
+	isTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	if opts.width <= 0 {
+		opts.width = 80
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && isTerminal {
+			opts.width = w
+		}
+	}
+	if opts.color {
+		// lipgloss looks at stdout too; keep its styles when piping with --color=always
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	}
+	return writePrint(os.Stdout, opts)
+}
+
+// writePrint renders opts to w, stripped of escapes unless opts.color.
+func writePrint(w io.Writer, opts printOptions) error {
+	out, err := renderPrint(opts)
+	if err != nil {
+		return err
+	}
+	if !opts.color {
+		// Without colors, the padding behind full-width headings is just noise
+		lines := strings.Split(xansi.Strip(out), "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimRight(l, " ")
+		}
+		out = strings.Join(lines, "\n")
+	}
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+// renderPrint produces the --print output: one file, or every changed file
+// as a list or as diffs.
+func renderPrint(opts printOptions) (string, error) {
+	if opts.file != "" && !opts.diff {
+		content, err := readFile(opts.file)
+		if err != nil {
+			return "", err
+		}
+		rendered, _ := renderFileContent(content, opts.file, isPreviewable(opts.file), opts.width)
+		return rendered, nil
+	}
+
+	files, err := getChangedFiles(opts.base)
+	if err != nil {
+		return "", err
+	}
+	if opts.file != "" {
+		status := ""
+		for _, f := range files {
+			if f.path == opts.file {
+				status = f.status
+			}
+		}
+		return printDiff(opts.file, status, opts.base)
+	}
+
+	if !opts.diff {
+		return formatFileList(files), nil
+	}
+	var diffs []string
+	for _, f := range files {
+		d, err := printDiff(f.path, f.status, opts.base)
+		if err != nil {
+			return "", err
+		}
+		if d != "" {
+			diffs = append(diffs, d)
+		}
+	}
+	return strings.Join(diffs, "\n"), nil
+}
+
+// formatFileList renders changed files as the file list shows them: badge,
+// index and worktree columns, path.
+func formatFileList(files []fileEntry) string {
+	var b strings.Builder
+	for _, f := range files {
+		b.WriteString(statusBadgeStyle(f.status).Render(statusLabel(f.status)) + " " +
+			renderStatusColumns(f) + " " + f.path + "\n")
+	}
+	return b.String()
+}
+
+// printDiff is the highlighted diff of every uncommitted change to path, or
+// against base. Untracked files show as wholly added.
+func printDiff(path, status, base string) (string, error) {
+	var diff string
+	var err error
+	switch {
+	case status == "??":
+		content, err := readFile(path)
+		if err != nil {
+			return "", err
+		}
+		if isBinary(content) {
+			return "", nil
+		}
+		diff = untrackedDiff(path, content)
+	case base != "":
+		diff, err = getDiff(path, false, base)
+	default:
+		diff, err = getHeadDiff(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	diff = strings.TrimRight(diff, "\n")
+	if strings.TrimSpace(diff) == "" {
+		return "", nil
+	}
+	return highlightDiff(diff, path), nil
+}
+
+// untrackedDiff writes content as the diff that would add path.
+func untrackedDiff(path, content string) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\nnew file\n--- /dev/null\n+++ b/%s\n", path, path, path)
+	if len(lines) > 0 {
+		fmt.Fprintf(&b, "@@ -0,0 +1,%d @@\n", len(lines))
+	}
+	for _, l := range lines {
+		b.WriteString("+" + l + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestPrintColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "") // set but empty, which doesn't count
+	cases := []struct {
+		mode     string
+		terminal bool
+		want     bool
+	}{
+		{"auto", true, true},
+		{"auto", false, false}, // piped
+		{"always", false, true},
+		{"never", true, false},
+	}
+	for _, c := range cases {
+		if got, err := printColor(c.mode, c.terminal); err != nil || got != c.want {
+			t.Errorf("printColor(%q, %v) = %v, %v; want %v", c.mode, c.terminal, got, err, c.want)
+		}
+	}
+	t.Setenv("NO_COLOR", "1")
+	if got, _ := printColor("auto", true); got {
+		t.Error("NO_COLOR should turn colors off")
+	}
+	if _, err := printColor("sometimes", true); err == nil {
+		t.Error("unknown mode should fail")
+	}
+}
+
+func TestUntrackedDiff(t *testing.T) {
+	want := "diff --git a/n.txt b/n.txt\nnew file\n--- /dev/null\n+++ b/n.txt\n@@ -0,0 +1,2 @@\n+one\n+two\n"
+	if got := untrackedDiff("n.txt", "one\ntwo\n"); got != want {
+		t.Errorf("untrackedDiff = %q, want %q", got, want)
+	}
+}
+
+// TestPrintGolden renders the fixtures as --print --color=never would.
+// Run with -update after an intended rendering change.
+func TestPrintGolden(t *testing.T) {
+	old := workDir
+	workDir = "fixtures"
+	t.Cleanup(func() { workDir = old })
+
+	for _, name := range []string{"example.md", "architecture.mmd"} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writePrint(&buf, printOptions{file: name, width: 80}); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("fixtures", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("output differs from %s; rerun with -update if intended", golden)
+			}
+		})
+	}
+}
@@ -291,6 +291,42 @@ func TestParseporcelainPreservesLeadingStatus(t *testing.T) {
 	}
 }
 
+func TestParsePorcelainColumns(t *testing.T) {
+	raw := "MM both.go\nM  staged.go\n M unstaged.go\nAM added.go\n?? new.go\nR  old.go -> renamed.go\n"
+	files := parsePorcelain(raw)
+	if len(files) != 6 {
+		t.Fatalf("parsed %d files, want 6", len(files))
+	}
+
+	cases := []struct {
+		path             string
+		status           string
+		staged, unstaged bool
+	}{
+		{"both.go", "M", true, true},
+		{"staged.go", "M", true, false},
+		{"unstaged.go", "M", false, true},
+		{"added.go", "A", true, true},
+		{"new.go", "??", false, false},
+		{"renamed.go", "R", true, false},
+	}
+	for i, tc := range cases {
+		f := files[i]
+		if f.path != tc.path {
+			t.Errorf("file %d path = %q, want %q", i, f.path, tc.path)
+		}
+		if f.status != tc.status {
+			t.Errorf("%s status = %q, want %q", tc.path, f.status, tc.status)
+		}
+		if f.hasStaged() != tc.staged {
+			t.Errorf("%s hasStaged = %v, want %v", tc.path, f.hasStaged(), tc.staged)
+		}
+		if f.hasUnstaged() != tc.unstaged {
+			t.Errorf("%s hasUnstaged = %v, want %v", tc.path, f.hasUnstaged(), tc.unstaged)
+		}
+	}
+}
+
 func TestParseporcelainTrimSpaceBug(t *testing.T) {
 	// Regression: prove that TrimSpace on the full output corrupts the first path
 	raw := " M src/client/components/hud/HUD.tsx\n M src/second.ts\n"
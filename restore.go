@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// restoreTarget is a state a file can be put back to: HEAD, the index, or
+// a snapshot seen this session.
+type restoreTarget struct {
+	name string // "HEAD", "the index", or the snapshot's time
+	obj  string // object name of the content
+	at   time.Time
+	stat diffStat // snapshots only
+}
+
+func (t restoreTarget) FilterValue() string { return t.name }
+
+func (t restoreTarget) snapshot() bool { return !t.at.IsZero() }
+
+// restoreTargets lists HEAD, the index, then every distinct snapshot of path
+// in the timeline, newest first.
+func restoreTargets(path string, timeline []changeEvent) []list.Item {
+	items := []list.Item{
+		restoreTarget{name: "HEAD", obj: "HEAD:" + path},
+		restoreTarget{name: "the index", obj: ":" + path},
+	}
+	seen := map[string]bool{}
+	for i := len(timeline) - 1; i >= 0; i-- {
+		e := timeline[i]
+		if e.path != path || e.blob == "" || seen[e.blob] {
+			continue
+		}
+		seen[e.blob] = true
+		items = append(items, restoreTarget{name: e.at.Format("15:04:05"), obj: e.blob, at: e.at, stat: e.stat})
+	}
+	return items
+}
+
+// restoredMsg reports a restore, with a snapshot of the content it replaced.
+type restoredMsg struct {
+	path, target string
+	before       string // blob of the file as it was, "" if not captured
+	at           time.Time
+	err          error
+}
+
+// restoreFile puts path on disk back to the content of obj, passed through
+// the repo's checkout filters, keeping a snapshot of what it replaces. The
+// index is left alone.
+func restoreFile(path string, t restoreTarget) tea.Cmd {
+	return func() tea.Msg {
+		msg := restoredMsg{path: path, target: t.name, at: time.Now()}
+		if _, err := gitCmd("cat-file", "-e", t.obj); err != nil {
+			msg.err = fmt.Errorf("%s isn't in %s", path, t.name)
+			return msg
+		}
+		args := []string{"cat-file", "--filters", t.obj}
+		if !strings.Contains(t.obj, ":") {
+			// A bare blob needs the path to pick its filters
+			args = []string{"cat-file", "--filters", "--path=" + path, t.obj}
+		}
+		content, err := gitCmd(args...)
+		if err != nil {
+			msg.err = fmt.Errorf("git cat-file %s: %w", t.obj, err)
+			return msg
+		}
+
+		msg.before = worktreeObject(path)
+		full := filepath.Join(workDir, path)
+		mode := os.FileMode(0o644)
+		if info, err := os.Lstat(full); err == nil && info.Mode().IsRegular() {
+			mode = info.Mode().Perm()
+		} else if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			msg.err = err
+			return msg
+		}
+		switch objectMode(path, t.obj) {
+		case "120000":
+			msg.err = replaceWithSymlink(full, content)
+			return msg
+		case "100755":
+			mode |= 0o111
+		case "100644":
+			mode &^= 0o111
+		}
+		// A symlink in the worktree is replaced, not written through
+		msg.err = replaceFileAtomic(full, []byte(content), mode)
+		return msg
+	}
+}
+
+// objectMode is the tree mode ("100644", "120000", ...) of path in HEAD or
+// the index for those targets, or "" for a snapshot blob.
+func objectMode(path, obj string) string {
+	var out string
+	switch {
+	case strings.HasPrefix(obj, ":"):
+		out, _ = gitCmd("ls-files", "--stage", "--", path)
+	case strings.Contains(obj, ":"):
+		rev, _, _ := strings.Cut(obj, ":")
+		out, _ = gitCmd("ls-tree", rev, "--", path)
+	}
+	mode, _, _ := strings.Cut(out, " ")
+	return mode
+}
+
+// replaceWithSymlink swaps path for a symlink to target, never writing
+// through a link that's there now.
+func replaceWithSymlink(path, target string) error {
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".owl-link")
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// preRestoreEvent is the timeline entry keeping a file's content from just
+// before a restore, so the restore can itself be undone from the restore
+// picker. There's none for a file that matched HEAD or whose content the
+// timeline already has as its latest state.
+func preRestoreEvent(prev snapshot, timeline []changeEvent, path, before string, at time.Time) (changeEvent, bool) {
+	f, ok := prev.files[path]
+	if !ok || before == "" {
+		return changeEvent{}, false
+	}
+	for i := len(timeline) - 1; i >= 0; i-- {
+		if timeline[i].path == path {
+			if timeline[i].blob == before {
+				return changeEvent{}, false
+			}
+			break
+		}
+	}
+	return changeEvent{
+		path:      path,
+		oldStatus: f.status,
+		status:    f.status,
+		stat:      f.stat,
+		oldBlob:   f.blob,
+		blob:      before,
+		at:        at,
+	}, true
+}
+
+// restored records what a restore replaced and reloads.
+func (m model) restored(msg restoredMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.setFlash(msg.err.Error(), true)
+		return m, tea.Batch(loadFiles(m.allFiles, m.base), m.reloadViewer())
+	}
+	desc := "restored " + msg.path + " to " + msg.target
+	if e, ok := preRestoreEvent(m.prevSnapshot, m.timeline, msg.path, msg.before, msg.at); ok {
+		m.recordTimeline([]changeEvent{e})
+		desc += " (old version kept)"
+		// The next refresh compares the restored file with what it replaced
+		f := m.prevSnapshot.files[msg.path]
+		f.blob = msg.before
+		m.prevSnapshot.files[msg.path] = f
+	}
+	m.setFlash(desc, false)
+	return m, tea.Batch(loadFiles(m.allFiles, m.base), m.reloadViewer())
+}
+
+// confirmRestore asks before putting path back to t.
+func (m *model) confirmRestore(path string, t restoreTarget) {
+	m.confirm = &confirmPrompt{
+		text:  "restore " + path + " to " + t.name,
+		onYes: restoreFile(path, t),
+	}
+}
+
+// restoreDelegate renders HEAD and the index by name, and snapshots with
+// their time and line counts.
+type restoreDelegate struct{}
+
+func (d restoreDelegate) Height() int                             { return 1 }
+func (d restoreDelegate) Spacing() int                            { return 0 }
+func (d restoreDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d restoreDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	t, ok := item.(restoreTarget)
+	if !ok {
+		return
+	}
+	isSelected := index == m.Index()
+
+	prefix := "  "
+	if isSelected {
+		prefix = cursorStyle.Render("> ")
+	}
+	nameStyle, timeStyle, dimStyle := pathFileStyle, logDateStyle, headerDimStyle
+	var bg lipgloss.TerminalColor
+	if isSelected {
+		bg = colorHighlight
+		nameStyle = nameStyle.Background(bg)
+		timeStyle = timeStyle.Background(bg)
+		dimStyle = dimStyle.Background(bg)
+	}
+	row := prefix
+	if t.snapshot() {
+		row += timeStyle.Render(t.name) + dimStyle.Render("  snapshot, "+relativeTime(t.at, time.Now())+"  ") + renderStat(t.stat, bg)
+	} else {
+		row += nameStyle.Render(t.name)
+	}
+
+	if isSelected {
+		if rowLen := lipgloss.Width(row); rowLen < m.Width() {
+			row += selectedRowStyle.Render(strings.Repeat(" ", m.Width()-rowLen))
+		}
+	}
+	fmt.Fprint(w, row)
+}
+
+// openRestore lists the states path can be restored to.
+func (m model) openRestore(path string) (tea.Model, tea.Cmd) {
+	if m.logCommit != nil {
+		m.setFlash("restore works on the working tree; leave the commit first", true)
+		return m, nil
+	}
+	m.restorePath = path
+	m.restorePrevView = m.currentView
+	m.currentView = restoreView
+	m.restoreList.ResetFilter()
+	m.restoreList.SetItems(restoreTargets(path, m.timeline))
+	m.restoreList.Select(0)
+	return m, nil
+}
+
+// updateRestore handles keys in the restore picker.
+func (m model) updateRestore(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Back):
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.restoreList.FilterState() == list.FilterApplied {
+			m.restoreList.ResetFilter()
+			return m, nil
+		}
+		m.currentView = m.restorePrevView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Open):
+		t, ok := m.restoreList.SelectedItem().(restoreTarget)
+		if !ok {
+			return m, nil
+		}
+		m.currentView = m.restorePrevView
+		m.confirmRestore(m.restorePath, t)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.restoreList, cmd = m.restoreList.Update(msg)
+	return m, cmd
+}
+
+// renderRestore draws the file being restored above its states.
+func (m model) renderRestore() string {
+	top := " " + filterPromptStyle.Render("restore ") + m.restorePath + headerDimStyle.Render(" to")
+	return top + "\n" + m.renderList(m.restoreList)
+}
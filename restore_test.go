@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestoreTargets(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	timeline := []changeEvent{
+		{path: "a.go", blob: "a1", at: at},
+		{path: "b.go", blob: "b1", at: at.Add(time.Second)},
+		{path: "a.go", blob: "a2", at: at.Add(2 * time.Second)},
+		{path: "a.go", blob: "a1", at: at.Add(3 * time.Second)}, // back to an earlier state
+		{path: "a.go", at: at.Add(4 * time.Second)},             // deleted, nothing to restore
+	}
+	var got []string
+	for _, it := range restoreTargets("a.go", timeline) {
+		got = append(got, it.(restoreTarget).obj)
+	}
+	want := []string{"HEAD:a.go", ":a.go", "a1", "a2"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("target %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPreRestoreEvent(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	prev := newSnapshot([]fileEntry{{status: "M", path: "a.go", blob: "a1"}})
+	timeline := []changeEvent{{path: "a.go", blob: "a1", at: at}}
+
+	e, ok := preRestoreEvent(prev, timeline, "a.go", "a2", at.Add(time.Second))
+	if !ok || e.oldBlob != "a1" || e.blob != "a2" || e.status != "M" {
+		t.Errorf("unsnapshotted edit: got %+v, %v", e, ok)
+	}
+	if _, ok := preRestoreEvent(prev, timeline, "a.go", "a1", at); ok {
+		t.Error("content the timeline has: want no event")
+	}
+	if _, ok := preRestoreEvent(prev, timeline, "b.go", "b1", at); ok {
+		t.Error("file matching HEAD: want no event")
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// compileSearch turns a search query into a regexp. As with vim's
+// smartcase, a query without capitals matches case-insensitively.
+func compileSearch(query string) (*regexp.Regexp, error) {
+	if query == "" {
+		return nil, nil
+	}
+	if strings.ToLower(query) == query {
+		query = "(?i)" + query
+	}
+	return regexp.Compile(query)
+}
+
+// matchSpans finds re in the visible text of line, ignoring empty matches.
+func matchSpans(line string, re *regexp.Regexp) []wordSpan {
+	var spans []wordSpan
+	for _, loc := range re.FindAllStringIndex(ansi.Strip(line), -1) {
+		if loc[1] > loc[0] {
+			spans = append(spans, wordSpan{from: loc[0], to: loc[1]})
+		}
+	}
+	return spans
+}
+
+// searchLines lists the lines of content with a match for re.
+func searchLines(content string, re *regexp.Regexp) []int {
+	var hits []int
+	for i, line := range strings.Split(content, "\n") {
+		if matchSpans(line, re) != nil {
+			hits = append(hits, i)
+		}
+	}
+	return hits
+}
+
+// highlightMatches paints every match of re in content.
+func highlightMatches(content string, re *regexp.Regexp) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if spans := matchSpans(line, re); spans != nil {
+			lines[i] = paintSpans(line, spans, searchMatchBgColor)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// paintSpans gives the spans of s the background paint, then hands back
+// whatever background the line had, so diff tints carry on after a match.
+// Span offsets count visible bytes, as in emphasizeSpans.
+func paintSpans(s string, spans []wordSpan, paint string) string {
+	var b strings.Builder
+	bg := "" // the line's own background at this point
+	inside := false
+	pos, next := 0, 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			if j < len(s) {
+				j++
+			}
+			seq := s[i:j]
+			b.WriteString(seq)
+			switch {
+			case seq == "\033[0m" || seq == "\033[m" || seq == "\033[49m":
+				bg = ""
+			case strings.Contains(seq, "[48;") || strings.Contains(seq, ";48;"):
+				bg = seq
+			default:
+				i = j
+				continue
+			}
+			if inside {
+				b.WriteString(paint) // keep the match painted over the reset
+			}
+			i = j
+			continue
+		}
+
+		for next < len(spans) && pos >= spans[next].to {
+			next++
+		}
+		want := next < len(spans) && pos >= spans[next].from
+		if want != inside {
+			if want {
+				b.WriteString(paint)
+			} else if bg != "" {
+				b.WriteString(bg)
+			} else {
+				b.WriteString("\033[49m")
+			}
+			inside = want
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(s[i : i+size])
+		i += size
+		pos += size
+	}
+	if inside {
+		b.WriteString("\033[49m")
+	}
+	return b.String()
+}
+
+// activeSearch is the pattern being highlighted: the one being typed while
+// the prompt is open, otherwise the last one entered.
+func (m model) activeSearch() *regexp.Regexp {
+	if m.searching {
+		re, err := compileSearch(m.searchInput.Value())
+		if err != nil {
+			return nil
+		}
+		return re
+	}
+	return m.search
+}
+
+// searchRows lists the viewer rows holding a match, in order.
+func (m model) searchRows() []int {
+	re := m.activeSearch()
+	if re == nil {
+		return nil
+	}
+	hits := searchLines(m.rawContent, re)
+	if !m.splitActive() {
+		return hits
+	}
+	splitRows := m.splitRows()
+	rows := make([]int, 0, len(hits))
+	for _, l := range hits {
+		r := rowForLine(splitRows, l)
+		if len(rows) == 0 || rows[len(rows)-1] != r {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// searchCounter reports where the cursor is among the matches, e.g. "/foo 2/5".
+func (m model) searchCounter() string {
+	rows := m.searchRows()
+	if len(rows) == 0 {
+		return fmt.Sprintf("/%s no matches", m.searchQuery)
+	}
+	at := 0
+	for _, r := range rows {
+		if r <= m.cursorLine {
+			at++
+		}
+	}
+	return fmt.Sprintf("/%s %d/%d", m.searchQuery, at, len(rows))
+}
+
+// openSearch shows the search prompt, seeded with the last query.
+func (m model) openSearch() (tea.Model, tea.Cmd) {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.PromptStyle = filterPromptStyle
+	ti.CharLimit = 0
+	ti.SetValue(m.searchQuery)
+	ti.CursorEnd()
+	m.searching = true
+	m.searchInput = ti
+	return m, m.searchInput.Focus()
+}
+
+// updateSearch handles keys in the search prompt. Matches are highlighted as
+// the query is typed; enter jumps to the next one.
+func (m model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		query := m.searchInput.Value()
+		re, err := compileSearch(query)
+		if err != nil {
+			m.setFlash("invalid pattern: "+err.Error(), true)
+			m.refreshViewport()
+			return m, nil
+		}
+		m.search, m.searchQuery = re, query
+		if re == nil {
+			m.refreshViewport()
+			return m, nil
+		}
+		// Like vim, a search starts just past the cursor
+		m.jumpToMatch(true)
+		return m, nil
+
+	case tea.KeyEsc:
+		m.searching = false
+		m.refreshViewport()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.refreshViewport()
+	return m, cmd
+}
+
+// clearSearch drops the highlights of the last search.
+func (m *model) clearSearch() {
+	m.search, m.searchQuery = nil, ""
+	m.refreshViewport()
+}
+
+// jumpToMatch moves the cursor to the next match after it, or the previous
+// one before it, wrapping around the file.
+func (m *model) jumpToMatch(forward bool) {
+	rows := m.searchRows()
+	if len(rows) == 0 {
+		m.setFlash(fmt.Sprintf("no matches for /%s", m.searchQuery), true)
+		m.refreshViewport()
+		return
+	}
+	target, wrapped := -1, false
+	if forward {
+		for _, r := range rows {
+			if r > m.cursorLine {
+				target = r
+				break
+			}
+		}
+		if target < 0 {
+			target, wrapped = rows[0], true
+		}
+	} else {
+		for i := len(rows) - 1; i >= 0; i-- {
+			if rows[i] < m.cursorLine {
+				target = rows[i]
+				break
+			}
+		}
+		if target < 0 {
+			target, wrapped = rows[len(rows)-1], true
+		}
+	}
+	if wrapped {
+		if forward {
+			m.setFlash("search hit bottom, continuing at top", false)
+		} else {
+			m.setFlash("search hit top, continuing at bottom", false)
+		}
+	}
+
+	m.cursorLine = target
+	m.refreshViewport()
+	// Center the match when it's off screen
+	if target < m.viewport.YOffset || target >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(max(target-m.viewport.Height/2, 0))
+	}
+}
+
+// refreshViewport re-renders the viewer in place.
+func (m *model) refreshViewport() {
+	yoff := m.viewport.YOffset
+	m.viewport.SetContent(m.viewportContent())
+	m.viewport.SetYOffset(yoff)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCompileSearchSmartcase(t *testing.T) {
+	re, err := compileSearch("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("FOO") {
+		t.Error("lowercase query should ignore case")
+	}
+	re, _ = compileSearch("Foo")
+	if re.MatchString("foo") {
+		t.Error("query with capitals should match case")
+	}
+	if re, err := compileSearch(""); re != nil || err != nil {
+		t.Errorf("empty query = %v, %v; want nil, nil", re, err)
+	}
+	if _, err := compileSearch("("); err == nil {
+		t.Error("invalid pattern should fail")
+	}
+}
+
+func TestSearchLinesIgnoresEscapes(t *testing.T) {
+	re, _ := compileSearch(`^func \w+`)
+	content := "package main\n\033[38;5;1mfunc\033[0m main() {}\n}\nx*"
+	if got, want := searchLines(content, re), []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("searchLines = %v, want %v", got, want)
+	}
+	// Empty matches don't count
+	re, _ = compileSearch("z*")
+	if got := searchLines(content, re); got != nil {
+		t.Errorf("empty matches = %v, want none", got)
+	}
+}
+
+func TestPaintSpans(t *testing.T) {
+	line := "ab\033[31mcd\033[0mef"
+	got := paintSpans(line, []wordSpan{{from: 1, to: 5}}, "\033[43m")
+	want := "a\033[43mb\033[31mcd\033[0m\033[43me\033[49mf"
+	if got != want {
+		t.Errorf("paintSpans = %q, want %q", got, want)
+	}
+	if ansi.Strip(got) != ansi.Strip(line) {
+		t.Error("painting changed the visible text")
+	}
+
+	// The line's own background comes back after a match
+	bg := "\033[48;2;1;2;3m"
+	got = paintSpans(bg+"abc", []wordSpan{{from: 0, to: 1}}, "\033[43m")
+	if want := bg + "\033[43ma" + bg + "bc"; got != want {
+		t.Errorf("paintSpans over bg = %q, want %q", got, want)
+	}
+}
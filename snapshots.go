@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snapshotMaxSize is the largest file whose content each refresh captures.
+const snapshotMaxSize = 4 << 20
+
+// capturedBlobs remembers the blob written for each file by size and mtime,
+// so a refresh only hashes the files that changed since the last one. It
+// holds one entry per path changed this session.
+var capturedBlobs = struct {
+	sync.Mutex
+	byPath map[string]capturedBlob
+}{byPath: map[string]capturedBlob{}}
+
+type capturedBlob struct {
+	size  int64
+	mtime time.Time
+	blob  string
+}
+
+// captureBlobs writes the content of each changed file to the object store
+// with `git hash-object -w` and records the blob on its entry. Deleted,
+// special and oversized files keep no blob, and files unchanged since they
+// were last captured reuse their blob. The objects are unreachable, so git
+// gc prunes them once they expire.
+func captureBlobs(files []fileEntry) {
+	capturedBlobs.Lock()
+	defer capturedBlobs.Unlock()
+	var paths []string
+	var idx []int
+	var infos []os.FileInfo
+	for i, f := range files {
+		info, err := os.Lstat(filepath.Join(workDir, f.path))
+		if err != nil || !info.Mode().IsRegular() || info.Size() > snapshotMaxSize || strings.Contains(f.path, "\n") {
+			continue
+		}
+		if c, ok := capturedBlobs.byPath[f.path]; ok && c.size == info.Size() && c.mtime.Equal(info.ModTime()) {
+			files[i].blob = c.blob
+			continue
+		}
+		paths = append(paths, f.path)
+		idx = append(idx, i)
+		infos = append(infos, info)
+	}
+	if len(paths) == 0 {
+		return
+	}
+	cmd := exec.Command("git", "hash-object", "-w", "--stdin-paths")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\n") + "\n")
+	out, err := cmd.Output()
+	if err != nil {
+		// A file vanished mid-scan; the next refresh captures the rest
+		return
+	}
+	hashes := strings.Fields(string(out))
+	for j, i := range idx {
+		if j < len(hashes) {
+			files[i].blob = hashes[j]
+			capturedBlobs.byPath[paths[j]] = capturedBlob{size: infos[j].Size(), mtime: infos[j].ModTime(), blob: hashes[j]}
+		}
+	}
+}
+
+// snapshotOrigin is the commit the changed files are compared against: the
+// base, or HEAD. Files outside the list match it. "" before the first commit.
+func snapshotOrigin(base string) string {
+	if base != "" {
+		rev, _ := resolveBase(base)
+		return rev
+	}
+	out, err := gitCmd("rev-parse", "--verify", "--quiet", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// originObject names path's blob in origin, for a file that matched it.
+func originObject(origin, path string) string {
+	if origin == "" {
+		return ""
+	}
+	return origin + ":" + path
+}
+
+// travelDiff is a past state of a file shown in the viewer: the step
+// from one snapshot to the next, or a snapshot compared with the worktree.
+type travelDiff struct {
+	from, to   string // object names; "" for no file
+	toWorktree bool   // compare with the file as it is now instead of to
+	at         time.Time
+}
+
+// label describes the comparison for the breadcrumb.
+func (t travelDiff) label() string {
+	if t.toWorktree {
+		return t.at.Format("15:04:05") + " → now"
+	}
+	return "step " + t.at.Format("15:04:05")
+}
+
+// resolveObject turns an object name into a blob hash, writing the empty
+// blob for "" or a path the commit doesn't have.
+func resolveObject(name string) (string, error) {
+	if name != "" {
+		if out, err := gitCmd("rev-parse", "--verify", "--quiet", name); err == nil {
+			return strings.TrimSpace(out), nil
+		}
+	}
+	cmd := exec.Command("git", "hash-object", "-w", "--stdin")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// worktreeObject captures path as it is on disk, "" when it's gone.
+func worktreeObject(path string) string {
+	files := []fileEntry{{path: path}}
+	captureBlobs(files)
+	return files[0].blob
+}
+
+// loadTravelContent is loadFileContent for a past state of filename: the diff
+// between the two states, or the later state's content.
+func loadTravelContent(t travelDiff, filename string, diffMode, mdPreview bool, seq, width int) tea.Cmd {
+	return func() tea.Msg {
+		to := t.to
+		if t.toWorktree {
+			to = worktreeObject(filename)
+		}
+		if !diffMode {
+			content, err := gitCmd("cat-file", "-p", to)
+			if to == "" || err != nil {
+				return fileContentMsg{content: "(no file at this point)", filename: filename, seq: seq}
+			}
+			rendered, _ := renderFileContent(content, filename, mdPreview, width)
+			return fileContentMsg{content: rendered, filename: filename, seq: seq}
+		}
+
+		from, err := resolveObject(t.from)
+		if err == nil {
+			to, err = resolveObject(to)
+		}
+		if err != nil {
+			return fileContentMsg{err: err, filename: filename, seq: seq}
+		}
+		diff, err := gitCmd("diff", from, to)
+		if err != nil {
+			return fileContentMsg{err: err, filename: filename, seq: seq}
+		}
+		if strings.TrimSpace(diff) == "" {
+			return fileContentMsg{content: "(no changes)", filename: filename, seq: seq}
+		}
+		// git names the sides after the blobs
+		diff = strings.ReplaceAll(diff, "a/"+from, "a/"+filename)
+		diff = strings.ReplaceAll(diff, "b/"+to, "b/"+filename)
+		return fileContentMsg{content: highlightDiff(diff, filename), filename: filename, seq: seq}
+	}
+}
+
+// openTravel shows a past state of path in the viewer, coming back to the
+// timeline.
+func (m model) openTravel(path string, t travelDiff) (tea.Model, tea.Cmd) {
+	if t.from == "" && t.to == "" && !t.toWorktree {
+		m.setFlash(fmt.Sprintf("no snapshot of %s from %s", path, t.at.Format("15:04:05")), true)
+		return m, nil
+	}
+	m.travel = &t
+	m.returnViewerTo(timelineView)
+	m.currentFile = path
+	m.hScroll = 0
+	m.cursorLine = 0
+	m.diffMode = true
+	m.diffStaged = false
+	m.mdPreview = false
+	m.visual = false
+	m.loadSeq++
+	innerW, innerH := m.innerSize()
+	m.viewport = viewport.New(innerW-1, innerH-2)
+	m.viewport.SetContent("Loading...")
+	return m, m.loadContent(path, "")
+}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestSnapshotChangesBlobs(t *testing.T) {
+	prev := newSnapshot([]fileEntry{
+		{status: "M", path: "a.go", stat: diffStat{added: 1}, blob: "a1"},
+		{status: "M", path: "b.go", stat: diffStat{added: 1}, blob: "b1"},
+	})
+	prev.origin = "c1"
+	next := newSnapshot([]fileEntry{
+		{status: "M", path: "a.go", stat: diffStat{added: 1}, blob: "a2"}, // same size, new content
+		{status: "??", path: "n.go", stat: diffStat{added: 3}, blob: "n1"},
+		// b.go committed in c2
+	})
+	next.origin = "c2"
+
+	want := []struct{ path, oldBlob, blob string }{
+		{"a.go", "a1", "a2"},
+		{"b.go", "b1", "c2:b.go"},
+		{"n.go", "c1:n.go", "n1"},
+	}
+	got := prev.changes(next)
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].path != w.path || got[i].oldBlob != w.oldBlob || got[i].blob != w.blob {
+			t.Errorf("event %d: got %s %q→%q, want %s %q→%q",
+				i, got[i].path, got[i].oldBlob, got[i].blob, w.path, w.oldBlob, w.blob)
+		}
+	}
+
+	// Before the first commit there is nothing to name
+	prev.origin = ""
+	if got := prev.changes(next); got[2].oldBlob != "" {
+		t.Errorf("new file without an origin: got %q, want no blob", got[2].oldBlob)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// sortMode orders the changed-file list.
+type sortMode int
+
+const (
+	sortPath sortMode = iota
+	sortStatus
+	sortRecent // most recently changed this session first
+	sortChurn  // most lines changed first
+	sortExt
+)
+
+var sortModeNames = []string{"path", "status", "recent", "churn", "extension"}
+
+func (s sortMode) String() string { return sortModeNames[s] }
+
+// groupMode splits the changed-file list into sections.
+type groupMode int
+
+const (
+	groupNone groupMode = iota
+	groupStatus
+	groupDir
+)
+
+var groupModeNames = []string{"none", "status", "dir"}
+
+func (g groupMode) String() string { return groupModeNames[g] }
+
+func parseSortMode(name string) (sortMode, error) {
+	if i := slices.Index(sortModeNames, name); i >= 0 {
+		return sortMode(i), nil
+	}
+	return 0, fmt.Errorf("unknown sort %q (want %s)", name, strings.Join(sortModeNames, ", "))
+}
+
+func parseGroupMode(name string) (groupMode, error) {
+	if i := slices.Index(groupModeNames, name); i >= 0 {
+		return groupMode(i), nil
+	}
+	return 0, fmt.Errorf("unknown group %q (want %s)", name, strings.Join(groupModeNames, ", "))
+}
+
+// sectionHeader titles a group of files in the list. It never matches a
+// filter, so filtering shows the files alone.
+type sectionHeader struct {
+	title string
+	count int
+}
+
+func (h sectionHeader) FilterValue() string { return "" }
+
+// statusOrder ranks statuses for sorting and grouping: conflicts first,
+// untracked last.
+var statusOrder = []string{"U", "A", "M", "R", "C", "T", "D", "??"}
+
+func statusRank(status string) int {
+	if i := slices.Index(statusOrder, status); i >= 0 {
+		return i
+	}
+	return len(statusOrder)
+}
+
+// statusTitle names a status group.
+func statusTitle(status string) string {
+	switch status {
+	case "U":
+		return "conflicted"
+	case "A":
+		return "added"
+	case "M":
+		return "modified"
+	case "R":
+		return "renamed"
+	case "C":
+		return "copied"
+	case "T":
+		return "type changed"
+	case "D":
+		return "deleted"
+	case "??":
+		return "untracked"
+	}
+	return "other"
+}
+
+// lastChanged maps each path in the timeline to the time of its newest event.
+func lastChanged(timeline []changeEvent) map[string]time.Time {
+	at := make(map[string]time.Time, len(timeline))
+	for _, e := range timeline {
+		if e.at.After(at[e.path]) {
+			at[e.path] = e.at
+		}
+	}
+	return at
+}
+
+// sortFiles returns files ordered by mode, ties broken by path. Sorting by
+// recent puts files that haven't changed this session last.
+func sortFiles(files []fileEntry, mode sortMode, changed map[string]time.Time) []fileEntry {
+	sorted := slices.Clone(files)
+	slices.SortStableFunc(sorted, func(a, b fileEntry) int {
+		var c int
+		switch mode {
+		case sortStatus:
+			c = cmp.Compare(statusRank(a.status), statusRank(b.status))
+		case sortRecent:
+			c = changed[b.path].Compare(changed[a.path])
+		case sortChurn:
+			c = cmp.Compare(b.stat.added+b.stat.deleted, a.stat.added+a.stat.deleted)
+		case sortExt:
+			c = strings.Compare(filepath.Ext(a.path), filepath.Ext(b.path))
+		}
+		if c != 0 {
+			return c
+		}
+		return strings.Compare(a.path, b.path)
+	})
+	return sorted
+}
+
+// fileItems lays files out as list items, sorted, and in sections headed by
+// their status or directory when grouped. Statuses keep statusOrder and
+// directories sort by name, the repo root first.
+func fileItems(files []fileEntry, mode sortMode, group groupMode, changed map[string]time.Time) []list.Item {
+	sorted := sortFiles(files, mode, changed)
+	items := make([]list.Item, 0, len(sorted))
+	if group == groupNone {
+		for _, f := range sorted {
+			items = append(items, f)
+		}
+		return items
+	}
+
+	section := func(f fileEntry) string {
+		if group == groupStatus {
+			return statusTitle(f.status)
+		}
+		dir, _ := splitPath(f.path)
+		return dir
+	}
+	// Stable, so each section keeps the sort order
+	slices.SortStableFunc(sorted, func(a, b fileEntry) int {
+		if group == groupStatus {
+			return cmp.Compare(statusRank(a.status), statusRank(b.status))
+		}
+		return strings.Compare(section(a), section(b))
+	})
+
+	for i := 0; i < len(sorted); {
+		title := section(sorted[i])
+		j := i
+		for j < len(sorted) && section(sorted[j]) == title {
+			j++
+		}
+		if group == groupDir {
+			title = cmp.Or(title, ".") + "/"
+		}
+		items = append(items, sectionHeader{title: title, count: j - i})
+		for _, f := range sorted[i:j] {
+			items = append(items, f)
+		}
+		i = j
+	}
+	return items
+}
+
+// listFiles collects the file entries of items, skipping section headers.
+func listFiles(items []list.Item) []fileEntry {
+	var files []fileEntry
+	for _, it := range items {
+		if f, ok := it.(fileEntry); ok {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// skipSectionHeader moves the selection off a section header, on in the
+// direction it was moving, or back when there's no file that way.
+func skipSectionHeader(l *list.Model, down bool) {
+	items := l.VisibleItems()
+	i := l.Index()
+	if i < 0 || i >= len(items) {
+		return
+	}
+	if _, ok := items[i].(sectionHeader); !ok {
+		return
+	}
+	step := 1
+	if !down {
+		step = -1
+	}
+	for _, s := range []int{step, -step} {
+		for j := i + s; j >= 0 && j < len(items); j += s {
+			if _, ok := items[j].(sectionHeader); !ok {
+				l.Select(j)
+				return
+			}
+		}
+	}
+}
+
+// setFileItems fills the file list with files in the chosen order and
+// sections, keeping the selection on the same file as they move.
+func (m *model) setFileItems(files []fileEntry) {
+	sel, _ := m.list.SelectedItem().(fileEntry)
+	m.list.SetItems(fileItems(files, m.fileSort, m.fileGroup, lastChanged(m.timeline)))
+	if sel.path != "" {
+		for i, it := range m.list.VisibleItems() {
+			if f, ok := it.(fileEntry); ok && f.path == sel.path {
+				m.list.Select(i)
+				break
+			}
+		}
+	}
+	skipSectionHeader(&m.list, true)
+}
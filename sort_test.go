@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// itemNames renders items as paths and "#title count" headers.
+func itemNames(items []list.Item) string {
+	var names []string
+	for _, it := range items {
+		switch it := it.(type) {
+		case fileEntry:
+			names = append(names, it.path)
+		case sectionHeader:
+			names = append(names, fmt.Sprintf("#%s %d", it.title, it.count))
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+func TestFileItems(t *testing.T) {
+	files := []fileEntry{
+		{status: "M", path: "src/b.go", stat: diffStat{added: 2}},
+		{status: "??", path: "notes.md", stat: diffStat{added: 40}},
+		{status: "A", path: "src/a.txt", stat: diffStat{added: 5, deleted: 5}},
+		{status: "M", path: "main.go", stat: diffStat{deleted: 1}},
+	}
+	t0 := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	changed := lastChanged([]changeEvent{
+		{path: "main.go", at: t0},
+		{path: "src/b.go", at: t0.Add(time.Second)},
+		{path: "main.go", at: t0.Add(2 * time.Second)},
+	})
+
+	tests := []struct {
+		mode  sortMode
+		group groupMode
+		want  string
+	}{
+		{sortPath, groupNone, "main.go notes.md src/a.txt src/b.go"},
+		{sortStatus, groupNone, "src/a.txt main.go src/b.go notes.md"},
+		{sortRecent, groupNone, "main.go src/b.go notes.md src/a.txt"},
+		{sortChurn, groupNone, "notes.md src/a.txt src/b.go main.go"},
+		{sortExt, groupNone, "main.go src/b.go notes.md src/a.txt"},
+		{sortPath, groupStatus, "#added 1 src/a.txt #modified 2 main.go src/b.go #untracked 1 notes.md"},
+		{sortChurn, groupDir, "#./ 2 notes.md main.go #src/ 2 src/a.txt src/b.go"},
+	}
+	for _, tt := range tests {
+		got := itemNames(fileItems(files, tt.mode, tt.group, changed))
+		if got != tt.want {
+			t.Errorf("sort %s, group %s:\n got %s\nwant %s", tt.mode, tt.group, got, tt.want)
+		}
+	}
+}
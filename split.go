@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// splitMinWidth is the narrowest panel that still fits two readable columns;
+// below it the split view falls back to unified.
+const splitMinWidth = 100
+
+// splitRow is one row of the side-by-side diff: indexes into the unified diff
+// lines for each side, -1 when that side is empty. Header and hunk lines span
+// both columns and have left == right.
+type splitRow struct {
+	left, right    int
+	oldNum, newNum int // file line numbers, 0 for none
+	full           bool
+}
+
+// line returns the unified diff line behind the row, preferring the new side.
+func (r splitRow) line() int {
+	if r.right >= 0 {
+		return r.right
+	}
+	return r.left
+}
+
+// splitDiffRows pairs each run of removed lines with the added lines that
+// follow it, so a modified line sits next to its replacement.
+func splitDiffRows(lines []string) []splitRow {
+	var rows []splitRow
+	var dels, adds []splitRow
+	flush := func() {
+		for i := 0; i < len(dels) || i < len(adds); i++ {
+			r := splitRow{left: -1, right: -1}
+			if i < len(dels) {
+				r.left, r.oldNum = dels[i].left, dels[i].oldNum
+			}
+			if i < len(adds) {
+				r.right, r.newNum = adds[i].right, adds[i].newNum
+			}
+			rows = append(rows, r)
+		}
+		dels, adds = dels[:0], adds[:0]
+	}
+
+	inHunk := false
+	oldLine, newLine := 0, 0
+	for i, line := range lines {
+		stripped := ansi.Strip(line)
+		switch {
+		case strings.HasPrefix(stripped, "@@"):
+			flush()
+			inHunk = true
+			oldLine, newLine = parseHunkStarts(stripped)
+			rows = append(rows, splitRow{left: i, right: i, full: true})
+		case !inHunk || strings.HasPrefix(stripped, `\`):
+			flush()
+			rows = append(rows, splitRow{left: i, right: i, full: true})
+		case strings.HasPrefix(stripped, "diff "):
+			// Next file in a multi-file diff
+			flush()
+			inHunk = false
+			rows = append(rows, splitRow{left: i, right: i, full: true})
+		case strings.HasPrefix(stripped, "-"):
+			if len(adds) > 0 {
+				// A removal after additions starts a new block
+				flush()
+			}
+			dels = append(dels, splitRow{left: i, oldNum: oldLine})
+			oldLine++
+		case strings.HasPrefix(stripped, "+"):
+			adds = append(adds, splitRow{right: i, newNum: newLine})
+			newLine++
+		default:
+			flush()
+			rows = append(rows, splitRow{left: i, right: i, oldNum: oldLine, newNum: newLine})
+			oldLine++
+			newLine++
+		}
+	}
+	flush()
+	return rows
+}
+
+// rowForLine returns the row showing unified diff line idx.
+func rowForLine(rows []splitRow, idx int) int {
+	for i, r := range rows {
+		if r.left == idx || r.right == idx {
+			return i
+		}
+	}
+	return 0
+}
+
+// renderSplit lays out a highlighted unified diff as two columns, old on the
+// left and new on the right, each with its own line-number gutter. cursorRow
+// and sel are in rows.
+func renderSplit(content string, offset, width, cursorRow int, sel lineRange) string {
+	content = strings.ReplaceAll(content, "\t", "    ")
+	lines := strings.Split(content, "\n")
+	rows := splitDiffRows(lines)
+
+	maxLabel := 3
+	for _, r := range rows {
+		for _, n := range []int{r.oldNum, r.newNum} {
+			if l := len(fmt.Sprint(n)); n > 0 && l > maxLabel {
+				maxLabel = l
+			}
+		}
+	}
+	gutterW := maxLabel + 2 // digits + bar + space
+	colW := (width - 1) / 2 // one column for the divider
+	contentW := colW - gutterW
+
+	out := make([]string, len(rows))
+	for i, r := range rows {
+		barStyle := lineBarStyle
+		numStyle := lineNumStyle.Width(maxLabel)
+		bar := "│"
+		if i == cursorRow {
+			barStyle = cursorBarStyle
+			numStyle = cursorNumHighlightStyle.Width(maxLabel)
+		} else if sel.contains(i) {
+			barStyle = selectionBarStyle
+			numStyle = cursorNumHighlightStyle.Width(maxLabel)
+		}
+		if sel.contains(i) {
+			bar = "┃"
+		}
+
+		var row string
+		if r.full {
+			line := lines[r.left]
+			if offset > 0 {
+				line = ansi.TruncateLeft(line, offset, "")
+			}
+			row = ansi.Truncate(numStyle.Render("")+barStyle.Render(bar)+" "+line, width, "")
+		} else {
+			side := func(idx, num int, bgEsc string) string {
+				if idx < 0 {
+					return strings.Repeat(" ", colW)
+				}
+				line := lines[idx]
+				if offset > 0 {
+					line = ansi.TruncateLeft(line, offset, "")
+				}
+				line = ansi.Truncate(line, contentW, "")
+				label := ""
+				if num > 0 {
+					label = fmt.Sprint(num)
+				}
+				cell := numStyle.Render(label) + barStyle.Render(bar) + " " + line
+				if pad := colW - lipgloss.Width(cell); pad > 0 {
+					cell += strings.Repeat(" ", pad)
+				}
+				if r.left != r.right {
+					// Changed line — tint like the unified view
+					cell = injectBg(cell, bgEsc)
+				}
+				return cell
+			}
+			row = side(r.left, r.oldNum, diffDeletedBgColor) +
+				separatorStyle.Render("│") +
+				side(r.right, r.newNum, diffAddedBgColor)
+		}
+
+		if i == cursorRow {
+			if w := lipgloss.Width(row); w < width {
+				row += strings.Repeat(" ", width-w)
+			}
+			row = cursorLineStyle.Width(width).Render(row)
+		}
+		out[i] = row
+	}
+	return strings.Join(out, "\n")
+}
+
+// splitActive reports whether the viewer currently shows a side-by-side diff.
+func (m model) splitActive() bool {
+	innerW, _ := m.innerSize()
+	return m.splitDiff && m.diffMode && !m.mdPreview && innerW-1 >= splitMinWidth
+}
+
+// splitRows pairs up the current diff for the side-by-side layout.
+func (m model) splitRows() []splitRow {
+	return splitDiffRows(strings.Split(m.rawContent, "\n"))
+}
+
+// lineCount is the number of display lines in the viewer.
+func (m model) lineCount() int {
+	if m.splitActive() {
+		return len(m.splitRows())
+	}
+	return strings.Count(m.rawContent, "\n") + 1
+}
+
+// diffLine maps a display line to its unified diff line.
+func (m model) diffLine(row int) int {
+	if !m.splitActive() {
+		return row
+	}
+	rows := m.splitRows()
+	if row < 0 || row >= len(rows) {
+		return row
+	}
+	return rows[row].line()
+}
+
+// diffSelection is selection in unified diff lines, covering both sides of
+// the selected rows in split mode.
+func (m model) diffSelection() lineRange {
+	sel := m.selection()
+	if sel == noRange || !m.splitActive() {
+		return sel
+	}
+	rows := m.splitRows()
+	if sel.to >= len(rows) {
+		sel.to = len(rows) - 1
+	}
+	first, last := rows[sel.from], rows[sel.to]
+	from, to := first.left, last.right
+	if from < 0 || (first.right >= 0 && first.right < from) {
+		from = first.right
+	}
+	if last.left > to {
+		to = last.left
+	}
+	return lineRange{from: from, to: to}
+}
+
+// remapCursor converts the cursor and selection anchor after the viewer
+// switched between unified and split layouts.
+func (m *model) remapCursor(wasSplit bool) {
+	if wasSplit == m.splitActive() {
+		return
+	}
+	rows := m.splitRows()
+	conv := func(i int) int {
+		if wasSplit {
+			if i >= 0 && i < len(rows) {
+				return rows[i].line()
+			}
+			return i
+		}
+		return rowForLine(rows, i)
+	}
+	m.cursorLine = conv(m.cursorLine)
+	m.visualAnchor = conv(m.visualAnchor)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitDiffRows(t *testing.T) {
+	diff := `diff --git a/f.txt b/f.txt
+--- a/f.txt
++++ b/f.txt
+@@ -1,4 +1,5 @@
+ a
+-b
+-c
++B
++C
++D
+ d
+-e
+\ No newline at end of file`
+	rows := splitDiffRows(strings.Split(diff, "\n"))
+
+	want := []splitRow{
+		{left: 0, right: 0, full: true},
+		{left: 1, right: 1, full: true},
+		{left: 2, right: 2, full: true},
+		{left: 3, right: 3, full: true},
+		{left: 4, right: 4, oldNum: 1, newNum: 1},
+		{left: 5, right: 7, oldNum: 2, newNum: 2},
+		{left: 6, right: 8, oldNum: 3, newNum: 3},
+		{left: -1, right: 9, newNum: 4},
+		{left: 10, right: 10, oldNum: 4, newNum: 5},
+		{left: 11, right: -1, oldNum: 5},
+		{left: 12, right: 12, full: true},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %d, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+
+	if got := rowForLine(rows, 8); got != 6 {
+		t.Errorf("rowForLine(8) = %d, want 6", got)
+	}
+	if got := rows[9].line(); got != 11 {
+		t.Errorf("deletion-only row line = %d, want 11", got)
+	}
+}
+
+func TestSplitDiffRowsSeparateBlocks(t *testing.T) {
+	// A removal after an addition must not be paired with the earlier add.
+	diff := "@@ -1,2 +1,2 @@\n+x\n-y\n"
+	rows := splitDiffRows(strings.Split(diff, "\n"))
+	if len(rows) != 4 {
+		t.Fatalf("rows = %d, want 4: %+v", len(rows), rows)
+	}
+	if rows[1].left != -1 || rows[1].right != 1 || rows[2].left != 2 || rows[2].right != -1 {
+		t.Errorf("unexpected pairing: %+v", rows[1:3])
+	}
+}
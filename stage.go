@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitActionMsg reports the result of a command that changed the index or worktree.
+type gitActionMsg struct {
+	desc string // success message for the command bar
+	err  error
+}
+
+// confirmPrompt is a pending y/n question shown in the command bar.
+type confirmPrompt struct {
+	text  string  // e.g. "discard hunk"
+	onYes tea.Cmd // run when the user presses y
+}
+
+type hunkAction int
+
+const (
+	hunkStage   hunkAction = iota // worktree → index
+	hunkUnstage                   // index → worktree
+	hunkDiscard                   // drop from worktree
+)
+
+// applyHunk stages, unstages or discards the hunk under the cursor, or only
+// the selected lines when a visual selection is active.
+func (m model) applyHunk(action hunkAction) (tea.Model, tea.Cmd) {
+	if m.diffMode && m.base != "" {
+		m.setFlash("staging works on uncommitted changes — clear the base first", true)
+		return m, nil
+	}
+	if !m.diffMode || m.rawDiff == "" {
+		return m, nil
+	}
+	d := parseDiff(m.rawDiff)
+	reverse := action != hunkStage
+
+	what := "hunk"
+	var patch string
+	if m.visual {
+		what = "lines"
+		p, ok := d.rangePatch(m.diffSelection(), reverse)
+		if !ok {
+			m.setFlash("no changed lines selected", true)
+			return m, nil
+		}
+		patch = p
+	} else {
+		idx := d.hunkAt(m.diffLine(m.cursorLine))
+		if idx < 0 {
+			m.setFlash("cursor is not on a hunk", true)
+			return m, nil
+		}
+		patch = d.hunkPatch(idx)
+	}
+
+	switch action {
+	case hunkStage:
+		if m.diffStaged {
+			m.setFlash(what+" already staged", true)
+			return m, nil
+		}
+		m.visual = false
+		return m, gitApplyCmd(patch, what+" staged", "--cached")
+	case hunkUnstage:
+		if !m.diffStaged {
+			m.setFlash(what+" not staged", true)
+			return m, nil
+		}
+		m.visual = false
+		return m, gitApplyCmd(patch, what+" unstaged", "--cached", "--reverse")
+	case hunkDiscard:
+		if m.diffStaged {
+			m.setFlash("unstage the "+what+" before discarding", true)
+			return m, nil
+		}
+		m.visual = false
+		yoff := m.viewport.YOffset
+		m.viewport.SetContent(m.viewportContent())
+		m.viewport.SetYOffset(yoff)
+		m.confirm = &confirmPrompt{
+			text:  "discard " + what,
+			onYes: gitApplyCmd(patch, what+" discarded", "--reverse"),
+		}
+	}
+	return m, nil
+}
+
+// selection returns the visual selection in display lines, or noRange.
+func (m model) selection() lineRange {
+	if !m.visual {
+		return noRange
+	}
+	if m.visualAnchor <= m.cursorLine {
+		return lineRange{from: m.visualAnchor, to: m.cursorLine}
+	}
+	return lineRange{from: m.cursorLine, to: m.visualAnchor}
+}
+
+// gitApplyCmd runs git apply with patch and reports the outcome as a gitActionMsg.
+func gitApplyCmd(patch, desc string, args ...string) tea.Cmd {
+	return func() tea.Msg {
+		if err := gitApply(patch, args...); err != nil {
+			return gitActionMsg{err: fmt.Errorf("git apply: %w", err)}
+		}
+		return gitActionMsg{desc: desc}
+	}
+}
+
+// updateConfirm resolves a pending confirmation: y runs it, anything else cancels.
+func (m model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.confirm
+	m.confirm = nil
+	if msg.String() == "y" || msg.String() == "Y" {
+		return m, c.onYes
+	}
+	return m, nil
+}
+
+// setFlash shows a short-lived status message in the command bar.
+func (m *model) setFlash(text string, isErr bool) {
+	if idx := strings.Index(text, "\n"); idx != -1 {
+		text = text[:idx]
+	}
+	m.flash = text
+	m.flashErr = isErr
+	m.flashUntil = time.Now().Add(4 * time.Second)
+}
+
+// reloadViewer re-reads the open file, keeping the scroll position.
+func (m *model) reloadViewer() tea.Cmd {
+	if m.currentView != fileViewerView || m.currentFile == "" {
+		return nil
+	}
+	m.loadSeq++
+	m.autoRefresh = true
+	item, ok := m.list.SelectedItem().(fileEntry)
+	status := ""
+	if ok {
+		status = item.status
+	}
+	return m.loadContent(m.currentFile, status)
+}
@@ -1,280 +1,572 @@
 package main
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"reflect"
+	"regexp"
 
-// Tokyo Night–inspired palette
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a complete palette. Colors are "#rrggbb" strings; the diff
+// backgrounds are turned into raw escapes, so other notations won't do.
+type Theme struct {
+	Chroma string `toml:"chroma"` // chroma style for syntax highlighting
+
+	// Base
+	Bg       string `toml:"bg"`
+	Fg       string `toml:"fg"`
+	FgDim    string `toml:"fg_dim"`
+	FgBright string `toml:"fg_bright"`
+
+	// Accents
+	Blue   string `toml:"blue"`
+	Cyan   string `toml:"cyan"`
+	Purple string `toml:"purple"`
+	Orange string `toml:"orange"`
+	Teal   string `toml:"teal"` // markdown code: types, classes
+	Sky    string `toml:"sky"`  // markdown code: operators, escapes
+
+	// Git status
+	Added     string `toml:"added"`
+	Modified  string `toml:"modified"`
+	Deleted   string `toml:"deleted"`
+	Renamed   string `toml:"renamed"`
+	Untracked string `toml:"untracked"`
+
+	// Surfaces
+	Surface     string `toml:"surface"`
+	SurfaceDim  string `toml:"surface_dim"`
+	Highlight   string `toml:"highlight"`
+	BorderDim   string `toml:"border_dim"`
+	BorderFocus string `toml:"border_focus"`
+
+	// Diff line backgrounds
+	AddedBg   string `toml:"added_bg"`
+	DeletedBg string `toml:"deleted_bg"`
+
+	// Markdown heading bars, H1 to H6
+	HeadingBg [6]string `toml:"heading_bg"`
+	HeadingFg [6]string `toml:"heading_fg"`
+}
+
+var hexColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validate checks every color is "#rrggbb" and the chroma style exists.
+func (t Theme) validate() error {
+	if _, ok := styles.Registry[t.Chroma]; !ok {
+		return fmt.Errorf("chroma: unknown style %q", t.Chroma)
+	}
+	v := reflect.ValueOf(t)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("toml")
+		switch f := v.Field(i); f.Kind() {
+		case reflect.String:
+			if name != "chroma" && !hexColorRe.MatchString(f.String()) {
+				return fmt.Errorf("%s: %q is not a #rrggbb color", name, f.String())
+			}
+		case reflect.Array:
+			for j := 0; j < f.Len(); j++ {
+				if c := f.Index(j).String(); !hexColorRe.MatchString(c) {
+					return fmt.Errorf("%s[%d]: %q is not a #rrggbb color", name, j, c)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// bgEscape turns "#rrggbb" into a 24-bit ANSI background escape.
+func bgEscape(hex string) string {
+	var r, g, b uint8
+	fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b)
+	return fmt.Sprintf("\033[48;2;%d;%d;%dm", r, g, b)
+}
+
+// mixHex blends two "#rrggbb" colors; t=0 gives a, t=1 gives b.
+func mixHex(a, b string, t float64) string {
+	var ac, bc [3]uint8
+	fmt.Sscanf(a, "#%02x%02x%02x", &ac[0], &ac[1], &ac[2])
+	fmt.Sscanf(b, "#%02x%02x%02x", &bc[0], &bc[1], &bc[2])
+	var out [3]uint8
+	for i := range out {
+		out[i] = uint8(float64(ac[i]) + (float64(bc[i])-float64(ac[i]))*t + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", out[0], out[1], out[2])
+}
+
+// Palette of the active theme, set by applyTheme
 var (
 	// Base
-	colorBg      = lipgloss.Color("#1a1b26")
-	colorFg      = lipgloss.Color("#a9b1d6")
-	colorFgDim   = lipgloss.Color("#565f89")
-	colorFgBright = lipgloss.Color("#c0caf5")
+	colorBg       lipgloss.Color
+	colorFg       lipgloss.Color
+	colorFgDim    lipgloss.Color
+	colorFgBright lipgloss.Color
 
 	// Accents
-	colorBlue   = lipgloss.Color("#7aa2f7")
-	colorCyan   = lipgloss.Color("#7dcfff")
-	colorPurple = lipgloss.Color("#bb9af7")
-	colorOrange = lipgloss.Color("#ff9e64")
+	colorBlue   lipgloss.Color
+	colorCyan   lipgloss.Color
+	colorPurple lipgloss.Color
+	colorOrange lipgloss.Color
 
 	// Git status
-	colorAdded     = lipgloss.Color("#9ece6a") // green
-	colorModified  = lipgloss.Color("#7aa2f7") // blue
-	colorDeleted   = lipgloss.Color("#f7768e") // red
-	colorRenamed   = lipgloss.Color("#ff9e64") // orange
-	colorUntracked = lipgloss.Color("#565f89") // dim gray
+	colorAdded     lipgloss.Color
+	colorModified  lipgloss.Color
+	colorDeleted   lipgloss.Color
+	colorRenamed   lipgloss.Color
+	colorUntracked lipgloss.Color
 
 	// Surfaces
-	colorSurface    = lipgloss.Color("#24283b")
-	colorSurfaceDim = lipgloss.Color("#1f2335")
-	colorHighlight  = lipgloss.Color("#292e42")
-	colorBorderDim  = lipgloss.Color("#3b4261")
-	colorBorderFocus = lipgloss.Color("#7aa2f7")
+	colorSurface     lipgloss.Color
+	colorSurfaceDim  lipgloss.Color
+	colorHighlight   lipgloss.Color
+	colorBorderDim   lipgloss.Color
+	colorBorderFocus lipgloss.Color
+
+	// Diff backgrounds
+	colorAddedBg   lipgloss.Color
+	colorDeletedBg lipgloss.Color
 )
 
-// ── Status bar ──────────────────────────────────────────────
+func init() {
+	applyTheme(builtinThemes[defaultThemeName])
+}
+
+// applyTheme makes t the active theme: palette, styles, markdown and
+// syntax highlighting.
+func applyTheme(t Theme) {
+	colorBg = lipgloss.Color(t.Bg)
+	colorFg = lipgloss.Color(t.Fg)
+	colorFgDim = lipgloss.Color(t.FgDim)
+	colorFgBright = lipgloss.Color(t.FgBright)
+
+	colorBlue = lipgloss.Color(t.Blue)
+	colorCyan = lipgloss.Color(t.Cyan)
+	colorPurple = lipgloss.Color(t.Purple)
+	colorOrange = lipgloss.Color(t.Orange)
+
+	colorAdded = lipgloss.Color(t.Added)
+	colorModified = lipgloss.Color(t.Modified)
+	colorDeleted = lipgloss.Color(t.Deleted)
+	colorRenamed = lipgloss.Color(t.Renamed)
+	colorUntracked = lipgloss.Color(t.Untracked)
+
+	colorSurface = lipgloss.Color(t.Surface)
+	colorSurfaceDim = lipgloss.Color(t.SurfaceDim)
+	colorHighlight = lipgloss.Color(t.Highlight)
+	colorBorderDim = lipgloss.Color(t.BorderDim)
+	colorBorderFocus = lipgloss.Color(t.BorderFocus)
+
+	colorAddedBg = lipgloss.Color(t.AddedBg)
+	colorDeletedBg = lipgloss.Color(t.DeletedBg)
+
+	buildStyles()
+	buildMarkdownStyles(t)
+	setHighlightStyle(t.Chroma)
+}
 
 var (
+	// ── Status bar ──────────────────────────────────────────────
+	logoBadge         lipgloss.Style
+	branchStyle       lipgloss.Style
+	fileCountStyle    lipgloss.Style
+	statusBarStyle    lipgloss.Style
+	headerLine2Style  lipgloss.Style
+	diffBadgeStyle    lipgloss.Style
+	allBadgeStyle     lipgloss.Style
+	previewBadgeStyle lipgloss.Style
+
+	// ── Command bar ─────────────────────────────────────────────
+	cmdBarStyle   lipgloss.Style
+	cmdKeyStyle   lipgloss.Style
+	cmdDescStyle  lipgloss.Style
+	cmdSepStyle   lipgloss.Style
+	flashStyle    lipgloss.Style
+	flashErrStyle lipgloss.Style
+
+	// ── File list delegate styles ───────────────────────────────
+	pathDirStyle         lipgloss.Style
+	pathFileStyle        lipgloss.Style
+	selectedRowStyle     lipgloss.Style
+	cursorStyle          lipgloss.Style
+	stagedColumnStyle    lipgloss.Style
+	unstagedColumnStyle  lipgloss.Style
+	untrackedColumnStyle lipgloss.Style
+	emptyColumnStyle     lipgloss.Style
+	sectionHeaderStyle   lipgloss.Style
+
+	// ── File viewer ─────────────────────────────────────────────
+	breadcrumbDirStyle  lipgloss.Style
+	breadcrumbFileStyle lipgloss.Style
+	breadcrumbSepStyle  lipgloss.Style
+	scrollPctStyle      lipgloss.Style
+	separatorStyle      lipgloss.Style
+	lineNumStyle        lipgloss.Style
+	lineBarStyle        lipgloss.Style
+	scrollThumbStyle    lipgloss.Style
+	scrollTrackStyle    lipgloss.Style
+
+	// ── Animation & header ──────────────────────────────────────
+	spinnerStyle            lipgloss.Style
+	owlStyle                lipgloss.Style
+	recentMarkerStyle       lipgloss.Style
+	headerDimStyle          lipgloss.Style
+	headerAccentStyle       lipgloss.Style
+	headerPulseLineStyle    lipgloss.Style
+	helpOverlayStyle        lipgloss.Style
+	helpSectionStyle        lipgloss.Style
+	dirtyIndicatorStyle     lipgloss.Style
+	cleanIndicatorStyle     lipgloss.Style
+	cursorLineStyle         lipgloss.Style
+	cursorBarStyle          lipgloss.Style
+	selectionBarStyle       lipgloss.Style
+	cursorNumHighlightStyle lipgloss.Style
+	diffAddedPrefixStyle    lipgloss.Style
+	diffDeletedPrefixStyle  lipgloss.Style
+	diffAddedBgStyle        lipgloss.Style
+	diffDeletedBgStyle      lipgloss.Style
+	diffHunkStyle           lipgloss.Style
+	diffHeaderStyle         lipgloss.Style
+	fixBadgeStyle           lipgloss.Style
+	stagedBadgeStyle        lipgloss.Style
+	unstagedBadgeStyle      lipgloss.Style
+
+	// Raw ANSI 24-bit background escapes for injecting into syntax-highlighted lines
+	diffAddedBgColor   string
+	diffDeletedBgColor string
+	// Stronger tints for the words that changed within a paired line
+	diffAddedEmphBgColor   string
+	diffDeletedEmphBgColor string
+	// Search matches in the viewer
+	searchMatchBgColor string
+
+	// ── History ─────────────────────────────────────────────────
+	logHashStyle      lipgloss.Style
+	logDateStyle      lipgloss.Style
+	logAuthorStyle    lipgloss.Style
+	logSubjectStyle   lipgloss.Style
+	historyBadgeStyle lipgloss.Style
+	baseBadgeStyle    lipgloss.Style
+
+	// ── Diffstat ────────────────────────────────────────────────
+	statAddedStyle   lipgloss.Style
+	statDeletedStyle lipgloss.Style
+
+	// ── Tree view ───────────────────────────────────────────────
+	treeFolderCollapsedStyle lipgloss.Style
+	treeFolderExpandedStyle  lipgloss.Style
+	treeFolderNameStyle      lipgloss.Style
+	treeBadgeStyle           lipgloss.Style
+
+	// ── Filter prompt ───────────────────────────────────────────
+	filterPromptStyle lipgloss.Style
+	searchMatchStyle  lipgloss.Style
+)
+
+// buildStyles recreates every style from the current palette.
+func buildStyles() {
+	// ── Status bar ──────────────────────────────────────────────
 	logoBadge = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#1a1b26")).
-			Background(colorBlue).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorBlue).
+		Padding(0, 1)
 
 	branchStyle = lipgloss.NewStyle().
-			Foreground(colorCyan).
-			Bold(true)
+		Foreground(colorCyan).
+		Bold(true)
 
 	fileCountStyle = lipgloss.NewStyle().
-			Foreground(colorFgDim)
+		Foreground(colorFgDim)
 
 	statusBarStyle = lipgloss.NewStyle().
-			Background(colorSurface).
-			Foreground(colorFg)
+		Background(colorSurface).
+		Foreground(colorFg)
 
 	headerLine2Style = lipgloss.NewStyle().
-				Foreground(colorFg)
+		Foreground(colorFg)
 
 	diffBadgeStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#1a1b26")).
-			Background(colorOrange).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorOrange).
+		Padding(0, 1)
 
 	allBadgeStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#1a1b26")).
-			Background(colorCyan).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorCyan).
+		Padding(0, 1)
 
 	previewBadgeStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#1a1b26")).
-				Background(colorCyan).
-				Padding(0, 1)
-)
-
-// ── Command bar ─────────────────────────────────────────────
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorCyan).
+		Padding(0, 1)
 
-var (
+	// ── Command bar ─────────────────────────────────────────────
 	cmdBarStyle = lipgloss.NewStyle().
-			Foreground(colorFg)
+		Foreground(colorFg)
 
 	cmdKeyStyle = lipgloss.NewStyle().
-			Foreground(colorCyan).
-			Bold(true)
+		Foreground(colorCyan).
+		Bold(true)
 
 	cmdDescStyle = lipgloss.NewStyle().
-			Foreground(colorFgDim)
+		Foreground(colorFgDim)
 
 	cmdSepStyle = lipgloss.NewStyle().
-			Foreground(colorBorderDim)
-)
-
-// ── Panel border ────────────────────────────────────────────
-
-func panelBorder(focused bool, width, height int) lipgloss.Style {
-	bc := colorBorderDim
-	if focused {
-		bc = colorBorderFocus
-	}
-	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(bc).
-		Width(width).
-		Height(height)
-}
+		Foreground(colorBorderDim)
 
-// ── File list delegate styles ───────────────────────────────
+	flashStyle = lipgloss.NewStyle().
+		Foreground(colorAdded)
 
-func statusBadgeStyle(status string) lipgloss.Style {
-	bg := statusColorForStatus(status)
-	return lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#1a1b26")).
-		Background(bg).
-		Width(3).
-		Align(lipgloss.Center)
-}
+	flashErrStyle = lipgloss.NewStyle().
+		Foreground(colorDeleted)
 
-var (
+	// ── File list delegate styles ───────────────────────────────
 	pathDirStyle = lipgloss.NewStyle().
-			Foreground(colorFgDim)
+		Foreground(colorFgDim)
 
 	pathFileStyle = lipgloss.NewStyle().
-			Foreground(colorFgBright).
-			Bold(true)
+		Foreground(colorFgBright).
+		Bold(true)
 
 	selectedRowStyle = lipgloss.NewStyle().
-				Background(colorHighlight)
+		Background(colorHighlight)
 
 	cursorStyle = lipgloss.NewStyle().
-			Foreground(colorCyan).
-			Bold(true)
-)
+		Foreground(colorCyan).
+		Bold(true)
 
-// ── File viewer ─────────────────────────────────────────────
+	stagedColumnStyle = lipgloss.NewStyle().
+		Foreground(colorAdded).
+		Bold(true)
 
-var (
+	unstagedColumnStyle = lipgloss.NewStyle().
+		Foreground(colorDeleted).
+		Bold(true)
+
+	untrackedColumnStyle = lipgloss.NewStyle().
+		Foreground(colorUntracked)
+
+	emptyColumnStyle = lipgloss.NewStyle().
+		Foreground(colorBorderDim)
+
+	sectionHeaderStyle = lipgloss.NewStyle().
+		Foreground(colorPurple).
+		Bold(true)
+
+	// ── File viewer ─────────────────────────────────────────────
 	breadcrumbDirStyle = lipgloss.NewStyle().
-				Foreground(colorFgDim)
+		Foreground(colorFgDim)
 
 	breadcrumbFileStyle = lipgloss.NewStyle().
-				Foreground(colorBlue).
-				Bold(true)
+		Foreground(colorBlue).
+		Bold(true)
 
 	breadcrumbSepStyle = lipgloss.NewStyle().
-				Foreground(colorBorderDim)
+		Foreground(colorBorderDim)
 
 	scrollPctStyle = lipgloss.NewStyle().
-			Foreground(colorFgDim)
+		Foreground(colorFgDim)
 
 	separatorStyle = lipgloss.NewStyle().
-			Foreground(colorBorderDim)
+		Foreground(colorBorderDim)
 
 	lineNumStyle = lipgloss.NewStyle().
-			Foreground(colorFgDim).
-			Align(lipgloss.Right).
-			Width(4)
+		Foreground(colorFgDim).
+		Align(lipgloss.Right).
+		Width(4)
 
 	lineBarStyle = lipgloss.NewStyle().
-			Foreground(colorBorderDim)
+		Foreground(colorBorderDim)
 
 	scrollThumbStyle = lipgloss.NewStyle().
-				Foreground(colorBlue)
+		Foreground(colorBlue)
 
 	scrollTrackStyle = lipgloss.NewStyle().
-				Foreground(colorBorderDim)
-)
-
-// ── Animation & header ──────────────────────────────────────
+		Foreground(colorBorderDim)
 
-var (
+	// ── Animation & header ──────────────────────────────────────
 	spinnerStyle = lipgloss.NewStyle().
-			Foreground(colorCyan)
+		Foreground(colorCyan)
 
 	owlStyle = lipgloss.NewStyle().
-			Foreground(colorCyan)
+		Foreground(colorCyan)
 
 	recentMarkerStyle = lipgloss.NewStyle().
-				Foreground(colorCyan).
-				Bold(true)
+		Foreground(colorCyan).
+		Bold(true)
 
 	headerDimStyle = lipgloss.NewStyle().
-			Foreground(colorFgDim)
+		Foreground(colorFgDim)
 
 	headerAccentStyle = lipgloss.NewStyle().
-				Foreground(colorCyan)
+		Foreground(colorCyan)
 
 	headerPulseLineStyle = lipgloss.NewStyle().
-				Foreground(colorCyan)
+		Foreground(colorCyan)
 
 	helpOverlayStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(colorBorderFocus).
-				Background(colorSurfaceDim).
-				Padding(1, 3).
-				Width(40)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorBorderFocus).
+		Background(colorSurfaceDim).
+		Padding(1, 3)
 
 	helpSectionStyle = lipgloss.NewStyle().
-				Foreground(colorPurple).
-				Bold(true)
+		Foreground(colorPurple).
+		Bold(true)
 
 	dirtyIndicatorStyle = lipgloss.NewStyle().
-				Foreground(colorOrange).
-				Bold(true)
+		Foreground(colorOrange).
+		Bold(true)
 
 	cleanIndicatorStyle = lipgloss.NewStyle().
-				Foreground(colorAdded).
-				Bold(true)
+		Foreground(colorAdded).
+		Bold(true)
 
 	cursorLineStyle = lipgloss.NewStyle().
-			Background(colorHighlight)
+		Background(colorHighlight)
 
 	cursorBarStyle = lipgloss.NewStyle().
-			Foreground(colorPurple).
-			Bold(true)
+		Foreground(colorPurple).
+		Bold(true)
+
+	selectionBarStyle = lipgloss.NewStyle().
+		Foreground(colorOrange).
+		Bold(true)
 
 	cursorNumHighlightStyle = lipgloss.NewStyle().
-				Foreground(colorPurple).
-				Bold(true).
-				Align(lipgloss.Right)
+		Foreground(colorPurple).
+		Bold(true).
+		Align(lipgloss.Right)
 
 	// Diff viewer styles
 	diffAddedPrefixStyle = lipgloss.NewStyle().
-				Foreground(colorAdded).
-				Bold(true)
+		Foreground(colorAdded).
+		Bold(true)
 
 	diffDeletedPrefixStyle = lipgloss.NewStyle().
-				Foreground(colorDeleted).
-				Bold(true)
+		Foreground(colorDeleted).
+		Bold(true)
 
 	diffAddedBgStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("#1a2e1a"))
+		Background(colorAddedBg)
 
 	diffDeletedBgStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("#2e1a1a"))
+		Background(colorDeletedBg)
 
-	// Raw ANSI 24-bit background escapes for injecting into syntax-highlighted lines
-	diffAddedBgColor   = "\033[48;2;26;46;26m"
-	diffDeletedBgColor = "\033[48;2;46;26;26m"
+	diffAddedBgColor = bgEscape(string(colorAddedBg))
+	diffDeletedBgColor = bgEscape(string(colorDeletedBg))
+	diffAddedEmphBgColor = bgEscape(mixHex(string(colorAddedBg), string(colorAdded), 0.3))
+	diffDeletedEmphBgColor = bgEscape(mixHex(string(colorDeletedBg), string(colorDeleted), 0.3))
+	searchMatchBgColor = bgEscape(mixHex(string(colorBg), string(colorOrange), 0.45))
 
 	diffHunkStyle = lipgloss.NewStyle().
-			Foreground(colorCyan)
+		Foreground(colorCyan)
 
 	diffHeaderStyle = lipgloss.NewStyle().
-			Foreground(colorFgDim)
+		Foreground(colorFgDim)
 
 	fixBadgeStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#1a1b26")).
-			Background(colorPurple).
-			Padding(0, 1)
-)
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorPurple).
+		Padding(0, 1)
 
-// ── Tree view ───────────────────────────────────────────────
+	stagedBadgeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorAdded).
+		Padding(0, 1)
 
-var (
+	unstagedBadgeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorDeleted).
+		Padding(0, 1)
+
+	// ── History ─────────────────────────────────────────────────
+	logHashStyle = lipgloss.NewStyle().
+		Foreground(colorOrange)
+
+	logDateStyle = lipgloss.NewStyle().
+		Foreground(colorFgDim)
+
+	logAuthorStyle = lipgloss.NewStyle().
+		Foreground(colorCyan)
+
+	logSubjectStyle = lipgloss.NewStyle().
+		Foreground(colorFgBright)
+
+	historyBadgeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorPurple).
+		Padding(0, 1)
+
+	baseBadgeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorCyan).
+		Padding(0, 1)
+
+	// ── Diffstat ────────────────────────────────────────────────
+	statAddedStyle = lipgloss.NewStyle().
+		Foreground(colorAdded)
+
+	statDeletedStyle = lipgloss.NewStyle().
+		Foreground(colorDeleted)
+
+	// ── Tree view ───────────────────────────────────────────────
 	treeFolderCollapsedStyle = lipgloss.NewStyle().
-				Foreground(colorCyan)
+		Foreground(colorCyan)
 
 	treeFolderExpandedStyle = lipgloss.NewStyle().
-				Foreground(colorCyan)
+		Foreground(colorCyan)
 
 	treeFolderNameStyle = lipgloss.NewStyle().
-				Foreground(colorBlue).
-				Bold(true)
+		Foreground(colorBlue).
+		Bold(true)
 
 	treeBadgeStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#1a1b26")).
-			Background(colorPurple).
-			Padding(0, 1)
-)
+		Bold(true).
+		Foreground(colorBg).
+		Background(colorPurple).
+		Padding(0, 1)
+
+	// ── Filter prompt ───────────────────────────────────────────
+	filterPromptStyle = lipgloss.NewStyle().
+		Foreground(colorCyan)
 
-// ── Filter prompt ───────────────────────────────────────────
+	searchMatchStyle = lipgloss.NewStyle().
+		Foreground(colorFgBright).
+		Background(lipgloss.Color(mixHex(string(colorBg), string(colorOrange), 0.45)))
+}
+
+func panelBorder(focused bool, width, height int) lipgloss.Style {
+	bc := colorBorderDim
+	if focused {
+		bc = colorBorderFocus
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(bc).
+		Width(width).
+		Height(height)
+}
 
-var filterPromptStyle = lipgloss.NewStyle().
-	Foreground(colorCyan)
+func statusBadgeStyle(status string) lipgloss.Style {
+	bg := statusColorForStatus(status)
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorBg).
+		Background(bg).
+		Width(3).
+		Align(lipgloss.Center)
+}
 
 // ── Helpers ─────────────────────────────────────────────────
 
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinThemesValid(t *testing.T) {
+	for name, th := range builtinThemes {
+		if err := th.validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestBgEscape(t *testing.T) {
+	if got := bgEscape("#1a2e1a"); got != "\033[48;2;26;46;26m" {
+		t.Errorf("bgEscape = %q", got)
+	}
+}
+
+func TestDecodeThemeWithBase(t *testing.T) {
+	th, err := decodeTheme("base = \"nord\"\nadded_bg = \"#003300\"\nheading_fg = [\"#ffffff\", \"#eeeeee\", \"#dddddd\", \"#cccccc\", \"#bbbbbb\", \"#aaaaaa\"]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.AddedBg != "#003300" || th.HeadingFg[5] != "#aaaaaa" {
+		t.Errorf("overrides not applied: %+v", th)
+	}
+	if th.Bg != builtinThemes["nord"].Bg || th.Chroma != "nord" {
+		t.Errorf("base colors lost: bg=%s chroma=%s", th.Bg, th.Chroma)
+	}
+}
+
+func TestDecodeThemeErrors(t *testing.T) {
+	for text, want := range map[string]string{
+		"base = \"nope\"\n":                           "unknown built-in theme",
+		"base = \"nord\"\nbackground = \"#000000\"\n": "unknown setting",
+		"base = \"nord\"\nfg = \"red\"\n":             "fg:",
+		"base = \"nord\"\nchroma = \"nope\"\n":        "chroma:",
+		"chroma = \"monokai\"\nfg = \"#000000\"\n":    "is not a #rrggbb color",
+	} {
+		_, err := decodeTheme(text)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("decodeTheme(%q) = %v, want error containing %q", text, err, want)
+		}
+	}
+}
+
+func TestLoadCustomTheme(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	dir := filepath.Join(home, "git-owl", "themes")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mine.toml"), []byte("base = \"tokyo-night-day\"\nblue = \"#0000ff\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := loadTheme("mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Blue != "#0000ff" || th.Chroma != "tokyonight-day" {
+		t.Errorf("got blue=%s chroma=%s", th.Blue, th.Chroma)
+	}
+	if _, err := loadTheme("missing"); err == nil || !strings.Contains(err.Error(), dir) {
+		t.Errorf("missing theme error should mention %s, got %v", dir, err)
+	}
+}
+
+func TestApplyThemeDerivesDiffBackground(t *testing.T) {
+	defer applyTheme(builtinThemes[defaultThemeName])
+	applyTheme(builtinThemes["tokyo-night-day"])
+	if diffAddedBgColor != bgEscape(builtinThemes["tokyo-night-day"].AddedBg) {
+		t.Errorf("diffAddedBgColor = %q", diffAddedBgColor)
+	}
+	if *mdStyle.Document.Color != builtinThemes["tokyo-night-day"].Fg {
+		t.Errorf("markdown fg = %s", *mdStyle.Document.Color)
+	}
+}
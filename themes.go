@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+const defaultThemeName = "tokyo-night"
+
+// builtinThemes are always available by name. Custom themes live in
+// <config home>/git-owl/themes/<name>.toml.
+var builtinThemes = map[string]Theme{
+	"tokyo-night": {
+		Chroma:   "monokai",
+		Bg:       "#1a1b26",
+		Fg:       "#a9b1d6",
+		FgDim:    "#565f89",
+		FgBright: "#c0caf5",
+
+		Blue:   "#7aa2f7",
+		Cyan:   "#7dcfff",
+		Purple: "#bb9af7",
+		Orange: "#ff9e64",
+		Teal:   "#2ac3de",
+		Sky:    "#89ddff",
+
+		Added:     "#9ece6a",
+		Modified:  "#7aa2f7",
+		Deleted:   "#f7768e",
+		Renamed:   "#ff9e64",
+		Untracked: "#565f89",
+
+		Surface:     "#24283b",
+		SurfaceDim:  "#1f2335",
+		Highlight:   "#292e42",
+		BorderDim:   "#3b4261",
+		BorderFocus: "#7aa2f7",
+
+		AddedBg:   "#1a2e1a",
+		DeletedBg: "#2e1a1a",
+
+		HeadingBg: [6]string{"#7aa2f7", "#3d59a1", "#2e3a5e", "#283350", "#24283b", "#1f2335"},
+		HeadingFg: [6]string{"#ffffff", "#c0caf5", "#a9b1d6", "#a9b1d6", "#7aa2f7", "#565f89"},
+	},
+
+	// Light variant for light terminal backgrounds
+	"tokyo-night-day": {
+		Chroma:   "tokyonight-day",
+		Bg:       "#e1e2e7",
+		Fg:       "#3760bf",
+		FgDim:    "#848cb5",
+		FgBright: "#343b58",
+
+		Blue:   "#2e7de9",
+		Cyan:   "#007197",
+		Purple: "#9854f1",
+		Orange: "#b15c00",
+		Teal:   "#118c74",
+		Sky:    "#006a83",
+
+		Added:     "#587539",
+		Modified:  "#2e7de9",
+		Deleted:   "#f52a65",
+		Renamed:   "#b15c00",
+		Untracked: "#848cb5",
+
+		Surface:     "#d0d5e3",
+		SurfaceDim:  "#e9e9ec",
+		Highlight:   "#c4c8da",
+		BorderDim:   "#a8aecb",
+		BorderFocus: "#2e7de9",
+
+		AddedBg:   "#d8e8cf",
+		DeletedBg: "#f4d7df",
+
+		HeadingBg: [6]string{"#2e7de9", "#5a97ec", "#8eb4ee", "#b4cbee", "#c4c8da", "#d0d5e3"},
+		HeadingFg: [6]string{"#ffffff", "#ffffff", "#343b58", "#343b58", "#2e7de9", "#848cb5"},
+	},
+
+	"gruvbox": {
+		Chroma:   "gruvbox",
+		Bg:       "#282828",
+		Fg:       "#ebdbb2",
+		FgDim:    "#928374",
+		FgBright: "#fbf1c7",
+
+		Blue:   "#83a598",
+		Cyan:   "#8ec07c",
+		Purple: "#d3869b",
+		Orange: "#fe8019",
+		Teal:   "#8ec07c",
+		Sky:    "#83a598",
+
+		Added:     "#b8bb26",
+		Modified:  "#83a598",
+		Deleted:   "#fb4934",
+		Renamed:   "#fabd2f",
+		Untracked: "#928374",
+
+		Surface:     "#3c3836",
+		SurfaceDim:  "#32302f",
+		Highlight:   "#504945",
+		BorderDim:   "#665c54",
+		BorderFocus: "#83a598",
+
+		AddedBg:   "#32361a",
+		DeletedBg: "#3c1f1e",
+
+		HeadingBg: [6]string{"#458588", "#3e6866", "#3a524f", "#3c3836", "#32302f", "#282828"},
+		HeadingFg: [6]string{"#fbf1c7", "#fbf1c7", "#ebdbb2", "#ebdbb2", "#83a598", "#928374"},
+	},
+
+	"nord": {
+		Chroma:   "nord",
+		Bg:       "#2e3440",
+		Fg:       "#d8dee9",
+		FgDim:    "#616e88",
+		FgBright: "#eceff4",
+
+		Blue:   "#81a1c1",
+		Cyan:   "#88c0d0",
+		Purple: "#b48ead",
+		Orange: "#d08770",
+		Teal:   "#8fbcbb",
+		Sky:    "#88c0d0",
+
+		Added:     "#a3be8c",
+		Modified:  "#81a1c1",
+		Deleted:   "#bf616a",
+		Renamed:   "#ebcb8b",
+		Untracked: "#616e88",
+
+		Surface:     "#3b4252",
+		SurfaceDim:  "#353b49",
+		Highlight:   "#434c5e",
+		BorderDim:   "#4c566a",
+		BorderFocus: "#88c0d0",
+
+		AddedBg:   "#34403a",
+		DeletedBg: "#45343b",
+
+		HeadingBg: [6]string{"#5e81ac", "#4c6a8f", "#435671", "#3b4252", "#353b49", "#2e3440"},
+		HeadingFg: [6]string{"#eceff4", "#eceff4", "#d8dee9", "#d8dee9", "#81a1c1", "#616e88"},
+	},
+}
+
+// themeNames lists the built-in themes, sorted.
+func themeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// themesDir is where custom theme files are looked up.
+func themesDir() string {
+	if home := configHome(); home != "" {
+		return filepath.Join(home, "git-owl", "themes")
+	}
+	return ""
+}
+
+// loadTheme resolves name to a built-in theme or a custom theme file.
+func loadTheme(name string) (Theme, error) {
+	if t, ok := builtinThemes[name]; ok {
+		return t, nil
+	}
+	dir := themesDir()
+	if dir == "" || strings.ContainsAny(name, `/\`) {
+		return Theme{}, fmt.Errorf("unknown theme %q (built-in: %s)", name, strings.Join(themeNames(), ", "))
+	}
+	path := filepath.Join(dir, name+".toml")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Theme{}, fmt.Errorf("unknown theme %q (built-in: %s; custom themes go in %s)",
+			name, strings.Join(themeNames(), ", "), dir)
+	}
+	if err != nil {
+		return Theme{}, err
+	}
+	t, err := decodeTheme(string(data))
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// themeFile is the on-disk format: an optional built-in base plus the
+// colors that differ from it. Without a base every color must be given.
+type themeFile struct {
+	Base  string `toml:"base"`
+	Theme `toml:",inline"`
+}
+
+func decodeTheme(text string) (Theme, error) {
+	var head struct {
+		Base string `toml:"base"`
+	}
+	if err := toml.Unmarshal([]byte(text), &head); err != nil {
+		return Theme{}, err
+	}
+	f := themeFile{Base: head.Base}
+	if head.Base != "" {
+		base, ok := builtinThemes[head.Base]
+		if !ok {
+			return Theme{}, fmt.Errorf("base: unknown built-in theme %q (have %s)", head.Base, strings.Join(themeNames(), ", "))
+		}
+		f.Theme = base
+	}
+
+	dec := toml.NewDecoder(strings.NewReader(text))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		var strict *toml.StrictMissingError
+		if errors.As(err, &strict) {
+			return Theme{}, fmt.Errorf("unknown setting:\n%s", strict.String())
+		}
+		return Theme{}, err
+	}
+	if err := f.Theme.validate(); err != nil {
+		return Theme{}, err
+	}
+	return f.Theme, nil
+}
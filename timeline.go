@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// timelineLimit caps how many change events the session timeline keeps.
+const timelineLimit = 5000
+
+func (e changeEvent) FilterValue() string { return e.path }
+
+// recordTimeline adds a refresh's events to the session timeline.
+func (m *model) recordTimeline(events []changeEvent) {
+	m.timeline = append(m.timeline, events...)
+	if len(m.timeline) > timelineLimit {
+		m.timeline = m.timeline[len(m.timeline)-timelineLimit:]
+	}
+	if m.currentView == timelineView && m.timelineList.FilterState() == list.Unfiltered {
+		// Keep the selection on the same event as new ones arrive on top
+		idx := m.timelineList.Index()
+		m.timelineList.SetItems(timelineItems(m.timeline))
+		if idx > 0 {
+			m.timelineList.Select(idx + len(events))
+		}
+	}
+}
+
+// timelineItems lists events newest first.
+func timelineItems(events []changeEvent) []list.Item {
+	items := make([]list.Item, len(events))
+	for i, e := range events {
+		items[len(events)-1-i] = e
+	}
+	return items
+}
+
+// timelineDelegate renders one change event per row: time, status
+// transition, path and the file's line counts after the change.
+type timelineDelegate struct{}
+
+func (d timelineDelegate) Height() int                             { return 1 }
+func (d timelineDelegate) Spacing() int                            { return 0 }
+func (d timelineDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d timelineDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	e, ok := item.(changeEvent)
+	if !ok {
+		return
+	}
+	isSelected := index == m.Index()
+	maxWidth := m.Width()
+
+	prefix := "  "
+	if isSelected {
+		prefix = cursorStyle.Render("> ")
+	}
+	timeStyle, dimStyle, dirStyle, fileStyle := logDateStyle, headerDimStyle, pathDirStyle, pathFileStyle
+	var bg lipgloss.TerminalColor
+	if isSelected {
+		bg = colorHighlight
+		timeStyle = timeStyle.Background(bg)
+		dimStyle = dimStyle.Background(bg)
+		dirStyle = dirStyle.Background(bg)
+		fileStyle = fileStyle.Background(bg)
+	}
+	badge := func(status string) string {
+		if status == "" {
+			return dimStyle.Render(" · ")
+		}
+		return statusBadgeStyle(status).Render(statusLabel(status))
+	}
+
+	row := prefix + timeStyle.Render(e.at.Format("15:04:05")) + dimStyle.Render(" ") +
+		badge(e.oldStatus) + dimStyle.Render(" → ") + badge(e.status) + dimStyle.Render(" ")
+
+	stat := ""
+	if e.status != "" {
+		stat = dimStyle.Render("  ") + renderStat(e.stat, bg)
+	}
+	dir, file := splitPath(e.path)
+	path := fileStyle.Render(file)
+	if dir != "" {
+		path = dirStyle.Render(dir+"/") + path
+	}
+	budget := maxWidth - lipgloss.Width(row) - lipgloss.Width(stat)
+	row += ansi.Truncate(path, max(budget, 10), "…") + stat
+
+	if isSelected {
+		if rowLen := lipgloss.Width(row); rowLen < maxWidth {
+			row += selectedRowStyle.Render(strings.Repeat(" ", maxWidth-rowLen))
+		}
+	}
+	fmt.Fprint(w, row)
+}
+
+// openTimeline lists every change event seen this session.
+func (m model) openTimeline() (tea.Model, tea.Cmd) {
+	m.timelinePrevView = m.currentView
+	m.currentView = timelineView
+	m.timelineList.ResetFilter()
+	m.timelineList.SetItems(timelineItems(m.timeline))
+	m.timelineList.Select(0)
+	return m, nil
+}
+
+// updateTimeline handles keys in the timeline view.
+func (m model) updateTimeline(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Back, m.keys.Timeline):
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.timelineList.FilterState() == list.FilterApplied {
+			m.timelineList.ResetFilter()
+			return m, nil
+		}
+		m.currentView = m.timelinePrevView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Open):
+		e, ok := m.timelineList.SelectedItem().(changeEvent)
+		if !ok {
+			return m, nil
+		}
+		if cfg.Snapshots.Enabled {
+			// What the change did, from the snapshot before it to the one after
+			return m.openTravel(e.path, travelDiff{from: e.oldBlob, to: e.blob, at: e.at})
+		}
+		// Without snapshots, show the file's change as it stands now
+		m.setFlash("showing the file as it is now; turn on [snapshots] to see each step", false)
+		m.returnViewerTo(timelineView)
+		m.currentFile = e.path
+		m.hScroll = 0
+		m.cursorLine = 0
+		m.diffStaged = false
+		m.mdPreview = false
+		m.visual = false
+		m.loadSeq++
+		innerW, innerH := m.innerSize()
+		m.viewport = viewport.New(innerW-1, innerH-2)
+		m.viewport.SetContent("Loading...")
+		return m, m.loadContent(e.path, e.status)
+
+	case key.Matches(msg, m.keys.Diff):
+		e, ok := m.timelineList.SelectedItem().(changeEvent)
+		if !ok {
+			return m, nil
+		}
+		if !cfg.Snapshots.Enabled {
+			m.setFlash("diffing from a past step needs [snapshots] enabled", true)
+			return m, nil
+		}
+		// Everything since: the snapshot after the change against the file now
+		return m.openTravel(e.path, travelDiff{from: e.blob, toWorktree: true, at: e.at})
+
+	case key.Matches(msg, m.keys.Restore):
+		e, ok := m.timelineList.SelectedItem().(changeEvent)
+		if !ok {
+			return m, nil
+		}
+		if e.blob == "" {
+			m.setFlash(fmt.Sprintf("no snapshot of %s from %s", e.path, e.at.Format("15:04:05")), true)
+			return m, nil
+		}
+		m.confirmRestore(e.path, restoreTarget{name: e.at.Format("15:04:05"), obj: e.blob, at: e.at})
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.timelineList, cmd = m.timelineList.Update(msg)
+	return m, cmd
+}
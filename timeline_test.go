@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimelineItems(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	events := []changeEvent{
+		{path: "a.go", at: at},
+		{path: "b.go", at: at.Add(time.Second)},
+		{path: "c.go", at: at.Add(2 * time.Second)},
+	}
+	items := timelineItems(events)
+	for i, want := range []string{"c.go", "b.go", "a.go"} {
+		if got := items[i].(changeEvent).path; got != want {
+			t.Errorf("item %d: got %s, want %s (newest first)", i, got, want)
+		}
+	}
+}
+
+func TestRecordTimeline(t *testing.T) {
+	m := initialModel()
+	m.timelineList.SetSize(80, 20)
+	m.recordTimeline([]changeEvent{{path: "a.go"}, {path: "b.go"}, {path: "c.go"}})
+	m.currentView = timelineView
+	m.timelineList.SetItems(timelineItems(m.timeline))
+
+	// On a past event, the selection follows it down as new ones arrive
+	m.timelineList.Select(1) // b.go
+	m.recordTimeline([]changeEvent{{path: "d.go"}, {path: "e.go"}})
+	if got := m.timelineList.SelectedItem().(changeEvent).path; got != "b.go" {
+		t.Errorf("selection moved to %s, want b.go", got)
+	}
+	// On the newest event, it stays on top
+	m.timelineList.Select(0)
+	m.recordTimeline([]changeEvent{{path: "f.go"}})
+	if got := m.timelineList.SelectedItem().(changeEvent).path; got != "f.go" {
+		t.Errorf("selection on %s, want the new f.go", got)
+	}
+
+	// The oldest events drop off past the cap
+	var many []changeEvent
+	for i := range timelineLimit {
+		many = append(many, changeEvent{path: fmt.Sprintf("%d.go", i)})
+	}
+	m.recordTimeline(many)
+	if len(m.timeline) != timelineLimit {
+		t.Fatalf("timeline holds %d events, want %d", len(m.timeline), timelineLimit)
+	}
+	if first := m.timeline[0].path; first != "0.go" {
+		t.Errorf("oldest kept event is %s, want 0.go", first)
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// editRecord is one quick-fix edit: the lines before, starting at 0-based
+// line of path, were replaced by after.
+type editRecord struct {
+	path   string
+	line   int
+	before []string
+	after  []string
+	at     time.Time
+}
+
+// editHistory is the session's quick-fix edits as undo and redo stacks.
+type editHistory struct {
+	done   []editRecord
+	undone []editRecord // most recently undone last
+}
+
+// record adds a new edit, which drops anything left to redo.
+func (h *editHistory) record(r editRecord) {
+	h.done = append(h.done, r)
+	h.undone = nil
+}
+
+// same reports whether r and o are the same edit.
+func (r editRecord) same(o editRecord) bool {
+	return r.path == o.path && r.line == o.line && r.at.Equal(o.at)
+}
+
+// markUndone moves rec, once reverted, to the redo stack. It reports false,
+// leaving the stacks alone, when rec is no longer the latest edit because
+// another landed while the revert ran.
+func (h *editHistory) markUndone(rec editRecord) bool {
+	n := len(h.done)
+	if n == 0 || !h.done[n-1].same(rec) {
+		return false
+	}
+	h.undone = append(h.undone, h.done[n-1])
+	h.done = h.done[:n-1]
+	return true
+}
+
+// markRedone moves rec back once it has been reapplied, if it's still the
+// latest undone edit.
+func (h *editHistory) markRedone(rec editRecord) bool {
+	n := len(h.undone)
+	if n == 0 || !h.undone[n-1].same(rec) {
+		return false
+	}
+	h.done = append(h.done, h.undone[n-1])
+	h.undone = h.undone[:n-1]
+	return true
+}
+
+// entries lists every edit newest first, undone ones included.
+func (h editHistory) entries() []list.Item {
+	items := make([]list.Item, 0, len(h.done)+len(h.undone))
+	for _, r := range h.undone {
+		items = append(items, editEntry{rec: r, undone: true})
+	}
+	for i := len(h.done) - 1; i >= 0; i-- {
+		items = append(items, editEntry{rec: h.done[i]})
+	}
+	return items
+}
+
+// editEntry is a row in the edit history panel.
+type editEntry struct {
+	rec    editRecord
+	undone bool
+}
+
+func (e editEntry) FilterValue() string { return e.rec.path }
+
+// quickFixSavedMsg reports a quick-fix write, carrying the reload that ran
+// right after it.
+type quickFixSavedMsg struct {
+	rec    editRecord
+	err    error
+	reload tea.Msg
+}
+
+// editRevertedMsg reports an undo (or redo) of rec.
+type editRevertedMsg struct {
+	rec  editRecord
+	redo bool
+	err  error
+}
+
+// writeAndReloadCmd saves a quick fix then immediately reloads the file content.
+// This avoids a race where loadFileContent reads before the write finishes.
+// base is what the edit started from (see saveQuickFix).
+func writeAndReloadCmd(path string, base *editBase, from, to int, newLines []string, reload tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		before, at, err := saveQuickFix(path, base, from, to, newLines)
+		rec := editRecord{path: path, line: at, before: before, after: newLines, at: time.Now()}
+		msg := quickFixSavedMsg{rec: rec, err: err}
+		if reload != nil {
+			// Now load inline — reuse the same logic as loadFileContent
+			msg.reload = reload()
+		}
+		return msg
+	}
+}
+
+// revertEditCmd undoes rec, or reapplies it for redo, as long as the file
+// still holds what the edit left there.
+func revertEditCmd(rec editRecord, redo bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if redo {
+			err = swapFileLines(rec.path, rec.line, rec.before, rec.after)
+		} else {
+			err = swapFileLines(rec.path, rec.line, rec.after, rec.before)
+		}
+		return editRevertedMsg{rec: rec, redo: redo, err: err}
+	}
+}
+
+// undoEdit reverts the latest quick-fix edit.
+func (m model) undoEdit() (tea.Model, tea.Cmd) {
+	if len(m.edits.done) == 0 {
+		m.setFlash("nothing to undo", true)
+		return m, nil
+	}
+	return m, revertEditCmd(m.edits.done[len(m.edits.done)-1], false)
+}
+
+// redoEdit reapplies the latest undone edit.
+func (m model) redoEdit() (tea.Model, tea.Cmd) {
+	if len(m.edits.undone) == 0 {
+		m.setFlash("nothing to redo", true)
+		return m, nil
+	}
+	return m, revertEditCmd(m.edits.undone[len(m.edits.undone)-1], true)
+}
+
+// editDelegate renders one edit per row: time, location, size and a preview
+// of the new text.
+type editDelegate struct{}
+
+func (d editDelegate) Height() int                             { return 1 }
+func (d editDelegate) Spacing() int                            { return 0 }
+func (d editDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d editDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	e, ok := item.(editEntry)
+	if !ok {
+		return
+	}
+	isSelected := index == m.Index()
+	maxWidth := m.Width()
+
+	prefix := "  "
+	if isSelected {
+		prefix = cursorStyle.Render("> ")
+	}
+	timeStyle, locStyle, sizeStyle, textStyle := logDateStyle, pathFileStyle, headerDimStyle, logSubjectStyle
+	if e.undone {
+		locStyle, textStyle = headerDimStyle, headerDimStyle
+	}
+	if isSelected {
+		timeStyle = timeStyle.Background(colorHighlight)
+		locStyle = locStyle.Background(colorHighlight)
+		sizeStyle = sizeStyle.Background(colorHighlight)
+		textStyle = textStyle.Background(colorHighlight)
+	}
+	gap := " "
+	if isSelected {
+		gap = selectedRowStyle.Render(" ")
+	}
+
+	size := fmt.Sprintf("-%d +%d", len(e.rec.before), len(e.rec.after))
+	if e.undone {
+		size += " undone"
+	}
+	row := prefix + timeStyle.Render(e.rec.at.Format("15:04:05")) + gap +
+		locStyle.Render(fmt.Sprintf("%s:%d", e.rec.path, e.rec.line+1)) + gap +
+		sizeStyle.Render(size) + gap
+
+	preview := ""
+	if len(e.rec.after) > 0 {
+		preview = strings.TrimSpace(e.rec.after[0])
+	}
+	if budget := maxWidth - lipgloss.Width(row); budget > 0 {
+		row += textStyle.Render(ansi.Truncate(preview, budget, "…"))
+	}
+
+	if isSelected {
+		if rowLen := lipgloss.Width(row); rowLen < maxWidth {
+			row += selectedRowStyle.Render(strings.Repeat(" ", maxWidth-rowLen))
+		}
+	}
+	fmt.Fprint(w, row)
+}
+
+// openEditHistory lists the session's quick-fix edits.
+func (m model) openEditHistory() (tea.Model, tea.Cmd) {
+	m.editsPrevView = m.currentView
+	m.currentView = editsView
+	m.editList.ResetFilter()
+	m.editList.SetItems(m.edits.entries())
+	return m, nil
+}
+
+// updateEditHistory handles keys in the edit history panel.
+func (m model) updateEditHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Back, m.keys.EditHistory):
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.editList.FilterState() == list.FilterApplied {
+			m.editList.ResetFilter()
+			return m, nil
+		}
+		m.currentView = m.editsPrevView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Open):
+		e, ok := m.editList.SelectedItem().(editEntry)
+		if !ok {
+			return m, nil
+		}
+		// Open the file scrolled to the edit
+		m.currentFile = e.rec.path
+		m.hScroll = 0
+		m.diffStaged = false
+		m.mdPreview = false
+		m.cursorLine = 0
+		if !m.diffMode {
+			m.cursorLine = e.rec.line
+		}
+		m.quickFixPending = true
+		m.quickFixYOffset = max(m.cursorLine-3, 0)
+		m.loadSeq++
+		innerW, innerH := m.innerSize()
+		m.viewport = viewport.New(innerW-1, innerH-2)
+		m.viewport.SetContent("Loading...")
+		return m, m.loadContent(e.rec.path, "")
+	}
+
+	var cmd tea.Cmd
+	m.editList, cmd = m.editList.Update(msg)
+	return m, cmd
+}
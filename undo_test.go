@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEditHistory(t *testing.T) {
+	var h editHistory
+	a, b := editRecord{path: "a", line: 1}, editRecord{path: "b", line: 2}
+	h.record(a)
+	h.record(b)
+	if h.markUndone(a) {
+		t.Fatal("undoing an edit that isn't the latest: want no move")
+	}
+	h.markUndone(b)
+	if len(h.done) != 1 || len(h.undone) != 1 || h.undone[0].path != "b" {
+		t.Fatalf("after undo: done=%v undone=%v", h.done, h.undone)
+	}
+
+	items := h.entries()
+	if len(items) != 2 {
+		t.Fatalf("entries = %d, want 2", len(items))
+	}
+	if e := items[0].(editEntry); e.rec.path != "b" || !e.undone {
+		t.Errorf("newest entry = %+v, want undone b", e)
+	}
+
+	h.markRedone(b)
+	if len(h.done) != 2 || len(h.undone) != 0 {
+		t.Fatalf("after redo: done=%v undone=%v", h.done, h.undone)
+	}
+
+	// A new edit after an undo drops the redo stack
+	h.markUndone(b)
+	h.record(editRecord{path: "c"})
+	if len(h.undone) != 0 || len(h.done) != 2 {
+		t.Errorf("after new edit: done=%v undone=%v", h.done, h.undone)
+	}
+	// so a redo that was in flight finds nothing to move
+	if h.markRedone(b) || len(h.done) != 2 {
+		t.Errorf("stale redo moved a record: done=%v undone=%v", h.done, h.undone)
+	}
+}
+
+func TestSwapFileLines(t *testing.T) {
+	dir := t.TempDir()
+	old := workDir
+	workDir = dir
+	t.Cleanup(func() { workDir = old })
+
+	path := filepath.Join(dir, "f.txt")
+	os.WriteFile(path, []byte("a\nB\nc\n"), 0o644)
+
+	// Undo: B goes back to b
+	if err := swapFileLines("f.txt", 1, []string{"B"}, []string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "a\nb\nc\n" {
+		t.Errorf("after undo = %q", data)
+	}
+
+	// The file no longer has B there, so redoing a stale edit must fail
+	if err := swapFileLines("f.txt", 1, []string{"X"}, []string{"B"}); err == nil {
+		t.Error("stale edit: want error")
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != "a\nb\nc\n" {
+		t.Errorf("stale edit changed the file: %q", data)
+	}
+
+	// Undoing a pure deletion reinserts the lines
+	if err := swapFileLines("f.txt", 1, nil, []string{"x", "y"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != "a\nx\ny\nb\nc\n" {
+		t.Errorf("after reinsert = %q", data)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchMaxWait caps how long the debouncer holds a continuous burst of events.
+// The quiet period and poll intervals come from the [refresh] config.
+const watchMaxWait = time.Second
+
+var errWatchUnsupported = errors.New("filesystem watching is not supported on this platform")
+
+// fsWatcher delivers one signal per debounced burst of relevant changes in
+// the worktree or the git dir.
+type fsWatcher struct {
+	changes chan struct{}
+	stop    func()
+}
+
+type watcherStartedMsg struct {
+	w   *fsWatcher
+	err error
+}
+
+type fsChangedMsg struct{}
+
+// startWatchCmd starts the platform watcher for workDir.
+func startWatchCmd() tea.Cmd {
+	return func() tea.Msg {
+		gitDir, err := gitCmd("rev-parse", "--absolute-git-dir")
+		if err != nil {
+			return watcherStartedMsg{err: err}
+		}
+		w, err := startWatcher(workDir, strings.TrimSpace(gitDir))
+		return watcherStartedMsg{w: w, err: err}
+	}
+}
+
+// waitForChange blocks until the watcher reports a change.
+func waitForChange(w *fsWatcher) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-w.changes; !ok {
+			return nil
+		}
+		return fsChangedMsg{}
+	}
+}
+
+// isGitDirEvent reports whether a change at name, relative to the git dir,
+// should trigger a refresh: the index (staging), HEAD (checkout/commit) and
+// refs, loose or packed. Lock files and objects are noise.
+func isGitDirEvent(name string) bool {
+	if strings.HasSuffix(name, ".lock") {
+		return false
+	}
+	return name == "index" || name == "HEAD" || name == "packed-refs" || strings.HasPrefix(name, "refs/")
+}
+
+// debounce reads raw repo-relative paths and emits one signal on out per
+// burst, once no event has arrived for quiet (or maxWait has passed since
+// the first one). Bursts where every path is ignored are dropped. Paths
+// prefixed with gitDirPrefix bypass the ignore check.
+func debounce(raw <-chan string, out chan<- struct{}, quiet, maxWait time.Duration, ignored func([]string) map[string]bool) {
+	defer close(out)
+	for {
+		first, ok := <-raw
+		if !ok {
+			return
+		}
+		batch := []string{first}
+		deadline := time.After(maxWait)
+	collect:
+		for {
+			select {
+			case p, ok := <-raw:
+				if !ok {
+					return
+				}
+				batch = append(batch, p)
+			case <-time.After(quiet):
+				break collect
+			case <-deadline:
+				break collect
+			}
+		}
+		if relevant(batch, ignored) {
+			select {
+			case out <- struct{}{}:
+			default:
+				// A signal is already pending — the refresh it triggers covers this burst too
+			}
+		}
+	}
+}
+
+const gitDirPrefix = "\x00git/"
+
+// relevant reports whether any path in batch is a git dir event or a
+// worktree path that .gitignore doesn't exclude.
+func relevant(batch []string, ignored func([]string) map[string]bool) bool {
+	var worktree []string
+	seen := map[string]bool{}
+	for _, p := range batch {
+		if strings.HasPrefix(p, gitDirPrefix) {
+			return true
+		}
+		if !seen[p] {
+			seen[p] = true
+			worktree = append(worktree, p)
+		}
+	}
+	ign := ignored(worktree)
+	for _, p := range worktree {
+		if !ign[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIgnored returns the subset of repo-relative paths excluded by .gitignore.
+func checkIgnored(paths []string) map[string]bool {
+	ignored := map[string]bool{}
+	if len(paths) == 0 {
+		return ignored
+	}
+	cmd := exec.Command("git", "check-ignore", "--stdin")
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\n") + "\n")
+	// Exit status 1 just means nothing matched
+	out, _ := cmd.Output()
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			ignored[line] = true
+		}
+	}
+	return ignored
+}
+
+// relPath converts an absolute path under root to a slash-separated repo path.
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
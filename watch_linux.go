@@ -0,0 +1,203 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	worktreeMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE |
+		unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF
+	gitDirMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO
+)
+
+// inotifyWatcher tracks one watch descriptor per non-ignored worktree directory,
+// plus the git dir itself and every directory under its refs.
+type inotifyWatcher struct {
+	fd     int
+	root   string
+	gitDir string
+
+	mu   sync.Mutex
+	dirs map[int]string // wd → absolute directory
+}
+
+// startWatcher watches root recursively (skipping .git and ignored
+// directories), the top of gitDir and gitDir/refs recursively, using
+// inotify. Branch updates land in refs, not the top of gitDir.
+func startWatcher(root, gitDir string) (*fsWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	iw := &inotifyWatcher{fd: fd, root: root, gitDir: gitDir, dirs: map[int]string{}}
+
+	if err := iw.add(gitDir, gitDirMask); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	iw.addRefs(filepath.Join(gitDir, "refs"))
+	if err := iw.addTree(root); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	raw := make(chan string, 256)
+	out := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go iw.readLoop(raw, done)
+	go debounce(raw, out, cfg.Refresh.Debounce.Duration, watchMaxWait, checkIgnored)
+
+	var once sync.Once
+	return &fsWatcher{
+		changes: out,
+		stop:    func() { once.Do(func() { close(done) }) },
+	}, nil
+}
+
+func (iw *inotifyWatcher) add(dir string, mask uint32) error {
+	wd, err := unix.InotifyAddWatch(iw.fd, dir, mask)
+	if err != nil {
+		return err
+	}
+	iw.mu.Lock()
+	iw.dirs[wd] = dir
+	iw.mu.Unlock()
+	return nil
+}
+
+// addTree watches dir and its subdirectories breadth-first, asking git once
+// per level which of the children are ignored so trees like node_modules are
+// never walked.
+func (iw *inotifyWatcher) addTree(dir string) error {
+	level := []string{dir}
+	for len(level) > 0 {
+		var next []string
+		for _, d := range level {
+			if err := iw.add(d, worktreeMask); err != nil {
+				return err
+			}
+			entries, err := os.ReadDir(d)
+			if err != nil {
+				continue
+			}
+			for _, e := range entries {
+				if e.IsDir() && e.Name() != ".git" {
+					next = append(next, filepath.Join(d, e.Name()))
+				}
+			}
+		}
+		rel := make([]string, len(next))
+		for i, d := range next {
+			rel[i] = relPath(iw.root, d)
+		}
+		ignored := checkIgnored(rel)
+		level = level[:0]
+		for i, d := range next {
+			if !ignored[rel[i]] {
+				level = append(level, d)
+			}
+		}
+	}
+	return nil
+}
+
+// addRefs watches dir and every directory below it in the git dir's refs.
+// Refs are few and never ignored, so this is a plain walk.
+func (iw *inotifyWatcher) addRefs(dir string) {
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			_ = iw.add(path, gitDirMask)
+		}
+		return nil
+	})
+}
+
+// readLoop turns inotify events into repo-relative paths on raw until done closes.
+func (iw *inotifyWatcher) readLoop(raw chan<- string, done <-chan struct{}) {
+	defer close(raw)
+	defer unix.Close(iw.fd)
+
+	buf := make([]byte, 64*1024)
+	fds := []unix.PollFd{{Fd: int32(iw.fd), Events: unix.POLLIN}}
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		n, err := unix.Poll(fds, 500)
+		if err != nil && err != unix.EINTR {
+			return
+		}
+		if n <= 0 {
+			continue
+		}
+		n, err = unix.Read(iw.fd, buf)
+		if err != nil {
+			if err == unix.EAGAIN || err == unix.EINTR {
+				continue
+			}
+			return
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			nameBytes := buf[off+unix.SizeofInotifyEvent : off+unix.SizeofInotifyEvent+int(ev.Len)]
+			off += unix.SizeofInotifyEvent + int(ev.Len)
+
+			name := string(nameBytes)
+			for len(name) > 0 && name[len(name)-1] == 0 {
+				name = name[:len(name)-1]
+			}
+			if p, ok := iw.handle(int(ev.Wd), ev.Mask, name); ok {
+				raw <- p
+			}
+		}
+	}
+}
+
+// handle maps one event to a path for the debouncer, watching new
+// directories as they appear.
+func (iw *inotifyWatcher) handle(wd int, mask uint32, name string) (string, bool) {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		// Events were dropped — force a refresh
+		return gitDirPrefix + "overflow", true
+	}
+	iw.mu.Lock()
+	dir, ok := iw.dirs[wd]
+	if mask&unix.IN_IGNORED != 0 {
+		delete(iw.dirs, wd)
+	}
+	iw.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+
+	full := filepath.Join(dir, name)
+	if dir == iw.gitDir || strings.HasPrefix(dir, iw.gitDir+string(filepath.Separator)) {
+		rel := relPath(iw.gitDir, full)
+		if mask&unix.IN_ISDIR != 0 && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 && strings.HasPrefix(rel, "refs/") {
+			// A new namespace such as refs/heads/feature/
+			iw.addRefs(full)
+		}
+		if isGitDirEvent(rel) {
+			return gitDirPrefix + rel, true
+		}
+		return "", false
+	}
+
+	rel := relPath(iw.root, full)
+	if mask&unix.IN_ISDIR != 0 && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 && name != ".git" {
+		if !checkIgnored([]string{rel})[rel] {
+			_ = iw.addTree(full)
+		}
+	}
+	return rel, true
+}
@@ -0,0 +1,43 @@
+//go:build linux
+
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestHandleGitDirEvents(t *testing.T) {
+	iw := &inotifyWatcher{root: "/repo", gitDir: "/repo/.git", dirs: map[int]string{
+		1: "/repo/.git",
+		2: "/repo/.git/refs/heads",
+		3: "/repo/.git/refs/heads/feature",
+		4: "/repo/src",
+	}}
+	cases := []struct {
+		wd   int
+		mask uint32
+		name string
+		want string // "" for dropped
+	}{
+		{1, unix.IN_MOVED_TO, "index", gitDirPrefix + "index"},
+		{1, unix.IN_CREATE, "index.lock", ""},
+		{1, unix.IN_MOVED_TO, "packed-refs", gitDirPrefix + "packed-refs"},
+		{1, unix.IN_CLOSE_WRITE, "FETCH_HEAD", ""},
+		// update-ref, reset --soft and fetch rename a lock over the ref
+		{2, unix.IN_CREATE, "main.lock", ""},
+		{2, unix.IN_MOVED_TO, "main", gitDirPrefix + "refs/heads/main"},
+		{3, unix.IN_MOVED_TO, "x", gitDirPrefix + "refs/heads/feature/x"},
+		{4, unix.IN_MODIFY, "main.go", "src/main.go"},
+	}
+	for _, c := range cases {
+		got, ok := iw.handle(c.wd, c.mask, c.name)
+		if !ok {
+			got = ""
+		}
+		if got != c.want {
+			t.Errorf("event %q in wd %d: got %q, want %q", c.name, c.wd, got, c.want)
+		}
+	}
+}
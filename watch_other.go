@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// startWatcher is only implemented on Linux; elsewhere git-owl polls.
+func startWatcher(root, gitDir string) (*fsWatcher, error) {
+	return nil, errWatchUnsupported
+}
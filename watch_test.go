@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebounceCoalescesBurst(t *testing.T) {
+	raw := make(chan string)
+	out := make(chan struct{}, 1)
+	noneIgnored := func([]string) map[string]bool { return map[string]bool{} }
+	go debounce(raw, out, 20*time.Millisecond, time.Second, noneIgnored)
+
+	for i := 0; i < 5; i++ {
+		raw <- "main.go"
+	}
+	select {
+	case <-out:
+	case <-time.After(time.Second):
+		t.Fatal("expected a signal after the burst")
+	}
+	select {
+	case <-out:
+		t.Fatal("burst produced more than one signal")
+	case <-time.After(60 * time.Millisecond):
+	}
+	close(raw)
+}
+
+func TestDebounceDropsIgnoredBurst(t *testing.T) {
+	raw := make(chan string)
+	out := make(chan struct{}, 1)
+	allIgnored := func(paths []string) map[string]bool {
+		m := map[string]bool{}
+		for _, p := range paths {
+			m[p] = true
+		}
+		return m
+	}
+	go debounce(raw, out, 20*time.Millisecond, time.Second, allIgnored)
+
+	raw <- "build/out.o"
+	select {
+	case <-out:
+		t.Fatal("ignored paths should not trigger a refresh")
+	case <-time.After(80 * time.Millisecond):
+	}
+
+	// Git dir events bypass the ignore check
+	raw <- gitDirPrefix + "index"
+	select {
+	case <-out:
+	case <-time.After(time.Second):
+		t.Fatal("index change should trigger a refresh")
+	}
+	close(raw)
+}
+
+func TestIsGitDirEvent(t *testing.T) {
+	cases := map[string]bool{
+		"index":                  true,
+		"HEAD":                   true,
+		"index.lock":             false,
+		"HEAD.lock":              false,
+		"objects":                false,
+		"ORIG_HEAD":              false,
+		"packed-refs":            true,
+		"refs/heads/main":        true,
+		"refs/heads/feature/x":   true,
+		"refs/heads/main.lock":   false,
+		"refs/remotes/origin/up": true,
+	}
+	for name, want := range cases {
+		if got := isGitDirEvent(name); got != want {
+			t.Errorf("isGitDirEvent(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordDiffMaxCells bounds the LCS table; longer line pairs are left with
+// plain line tints.
+const wordDiffMaxCells = 200_000
+
+// wordSpan is a byte range [from, to) of a line's plain text.
+type wordSpan struct {
+	from, to int
+}
+
+// diffTokens splits s into identifier-like words, whitespace runs and single
+// punctuation characters, so a renamed identifier is one token.
+func diffTokens(s string) []string {
+	var tokens []string
+	class := func(r rune) int {
+		switch {
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		default:
+			return 0
+		}
+	}
+	start, prev := 0, -1
+	for i, r := range s {
+		c := class(r)
+		if i > start && (c != prev || c == 0) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		prev = c
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// wordDiff returns the spans of oldLine and newLine that the two lines don't
+// share. Both are nil when the lines have too little in common for
+// emphasis to help, i.e. the line was rewritten rather than edited.
+func wordDiff(oldLine, newLine string) (oldSpans, newSpans []wordSpan) {
+	a, b := diffTokens(oldLine), diffTokens(newLine)
+	if len(a) == 0 || len(b) == 0 || (len(a)+1)*(len(b)+1) > wordDiffMaxCells {
+		return nil, nil
+	}
+
+	// lcs[i][j] is the common subsequence length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	keepA, keepB := make([]bool, len(a)), make([]bool, len(b))
+	common := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			keepA[i], keepB[j] = true, true
+			if strings.TrimSpace(a[i]) != "" {
+				common += len(a[i])
+			}
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	longest := max(len(strings.TrimSpace(oldLine)), len(strings.TrimSpace(newLine)))
+	if common*3 < longest {
+		return nil, nil
+	}
+	return changedSpans(a, keepA), changedSpans(b, keepB)
+}
+
+// changedSpans merges runs of unkept tokens into byte spans. Whitespace
+// between two changes joins them, so "foo bar" -> "baz qux" is one span.
+func changedSpans(tokens []string, keep []bool) []wordSpan {
+	var spans []wordSpan
+	pos := 0
+	for i, tok := range tokens {
+		end := pos + len(tok)
+		if !keep[i] {
+			if n := len(spans); n > 0 && spans[n-1].to == pos {
+				spans[n-1].to = end
+			} else {
+				spans = append(spans, wordSpan{pos, end})
+			}
+		} else if n := len(spans); n > 0 && spans[n-1].to == pos && strings.TrimSpace(tok) == "" &&
+			i+1 < len(tokens) && !keep[i+1] {
+			spans[n-1].to = end
+		}
+		pos = end
+	}
+	return spans
+}
+
+// emphasizeSpans is injectBg for a highlighted line whose changed spans get
+// emphBg instead of lineBg. Span offsets count visible bytes, skipping the
+// ANSI escapes chroma added.
+func emphasizeSpans(s string, spans []wordSpan, lineBg, emphBg string) string {
+	var b strings.Builder
+	b.WriteString(lineBg)
+	cur := lineBg
+	pos, next := 0, 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			if j < len(s) {
+				j++
+			}
+			seq := s[i:j]
+			b.WriteString(seq)
+			if seq == "\033[0m" || seq == "\033[m" {
+				b.WriteString(cur)
+			}
+			i = j
+			continue
+		}
+
+		for next < len(spans) && pos >= spans[next].to {
+			next++
+		}
+		want := lineBg
+		if next < len(spans) && pos >= spans[next].from {
+			want = emphBg
+		}
+		if want != cur {
+			b.WriteString(want)
+			cur = want
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(s[i : i+size])
+		i += size
+		pos += size
+	}
+	b.WriteString("\033[0m")
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDiffTokens(t *testing.T) {
+	got := diffTokens("foo_bar(x, 42)  // ok")
+	want := []string{"foo_bar", "(", "x", ",", " ", "42", ")", "  ", "/", "/", " ", "ok"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffTokens = %q, want %q", got, want)
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	oldLine := "\treturn fetchUser(ctx, id)"
+	newLine := "\treturn loadUser(ctx, id, opts)"
+	oldSpans, newSpans := wordDiff(oldLine, newLine)
+
+	var oldWords, newWords []string
+	for _, s := range oldSpans {
+		oldWords = append(oldWords, oldLine[s.from:s.to])
+	}
+	for _, s := range newSpans {
+		newWords = append(newWords, newLine[s.from:s.to])
+	}
+	if want := []string{"fetchUser"}; !reflect.DeepEqual(oldWords, want) {
+		t.Errorf("old spans = %q, want %q", oldWords, want)
+	}
+	if want := []string{"loadUser", ", opts"}; !reflect.DeepEqual(newWords, want) {
+		t.Errorf("new spans = %q, want %q", newWords, want)
+	}
+}
+
+func TestWordDiffRewrite(t *testing.T) {
+	// Nothing meaningful in common: leave the plain line tint
+	oldSpans, newSpans := wordDiff("x := compute(a, b)", "return errors.New(\"bad\")")
+	if oldSpans != nil || newSpans != nil {
+		t.Errorf("rewritten line got spans %v / %v", oldSpans, newSpans)
+	}
+}
+
+func TestEmphasizeSpans(t *testing.T) {
+	const line, emph = "<L>", "<E>"
+	// "ab" + red "cd" + "ef", emphasize "bcd"
+	s := "ab\033[31mcd\033[0mef"
+	got := emphasizeSpans(s, []wordSpan{{1, 4}}, line, emph)
+	want := "<L>a<E>b\033[31mcd\033[0m<E><L>ef\033[0m"
+	if got != want {
+		t.Errorf("emphasizeSpans = %q, want %q", got, want)
+	}
+
+	if got, want := emphasizeSpans(s, nil, line, emph), injectBg(s, line); got != want {
+		t.Errorf("no spans = %q, want injectBg's %q", got, want)
+	}
+}
+
+func TestHighlightDiffEmphasis(t *testing.T) {
+	diff := "@@ -1 +1 @@\n-total := price * qty\n+total := cost * qty"
+	out := strings.Split(highlightDiff(diff, "main.go"), "\n")
+	if len(out) != 3 {
+		t.Fatalf("lines = %d, want 3", len(out))
+	}
+	if got := ansi.Strip(out[2]); got != "+total := cost * qty" {
+		t.Errorf("added line text = %q", got)
+	}
+	if !strings.Contains(out[1], diffDeletedEmphBgColor) || !strings.Contains(out[2], diffAddedEmphBgColor) {
+		t.Error("paired lines are missing word emphasis")
+	}
+	// The unchanged tail must be back on the plain line tint
+	tail := out[2][strings.LastIndex(out[2], diffAddedEmphBgColor):]
+	if !strings.Contains(tail, diffAddedBgColor) {
+		t.Error("emphasis is not closed after the changed word")
+	}
+}